@@ -0,0 +1,45 @@
+package testaroli
+
+import "testing"
+
+func TestOverrideCardinalityWithinBounds(t *testing.T) {
+	ctx := TestingContext(t)
+	OverrideCardinality(ctx, bar, Between(2, 3), func(i int) error {
+		Expectation()
+		return nil
+	})(1)
+
+	testError(t, nil, bar(1))
+	testError(t, nil, bar(1))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestOverrideCardinalityBelowMinIsAnError(t *testing.T) {
+	ctx := TestingContext(t)
+	OverrideCardinality(ctx, bar, AtLeast(2), func(i int) error {
+		Expectation()
+		return nil
+	})(1)
+
+	testError(t, nil, bar(1))
+	if err := ExpectationsWereMet(); err == nil {
+		t.Error("expected an error, bar() was called fewer times than AtLeast(2) requires")
+	}
+}
+
+func TestOverrideCardinalityAdvancesChainAtUpperBound(t *testing.T) {
+	ctx := TestingContext(t)
+	OverrideCardinality(ctx, bar, Between(1, 2), func(i int) error {
+		Expectation()
+		return nil
+	})(1)
+	Override(ctx, baz, Once, func(i int) error {
+		Expectation()
+		return nil
+	})(2)
+
+	testError(t, nil, bar(1))
+	testError(t, nil, bar(1)) // reaches the upper bound, chain advances to baz()
+	testError(t, nil, baz(2))
+	testError(t, nil, ExpectationsWereMet())
+}
@@ -0,0 +1,38 @@
+package testaroli
+
+import "testing"
+
+func TestTestingContextCleanupReportsUnmetExpectation(t *testing.T) {
+	ok := t.Run("subtest", func(st *testing.T) {
+		Override(TestingContext(st), qux, Once, func(err error) error {
+			Expectation()
+			return nil
+		})
+		// deliberately never call qux - the Cleanup handler must report this via t.Errorf
+	})
+	if ok {
+		t.Error("expected the unmet Once expectation to fail the subtest via automatic cleanup")
+	}
+}
+
+func TestTestingContextCleanupRestoresPrologueOnPanic(t *testing.T) {
+	t.Run("subtest", func(st *testing.T) {
+		defer func() {
+			recover() // swallow the deliberate panic below, so it doesn't escape the subtest
+		}()
+		// Maybe, so the panic below leaves nothing for the Cleanup handler's ExpectationsWereMet to
+		// complain about - this test only cares whether the prologue itself gets restored
+		Override(TestingContext(st), qux, Maybe, func(err error) error {
+			Expectation()
+			return nil
+		})
+		panic("boom")
+	})
+
+	// the Cleanup handler must have restored qux's original prologue despite the panic, even though
+	// ExpectationsWereMet/Reset was never called by hand
+	err := bar(3) // bar(3) -> qux(errors.New("even")), unmodified once the prologue is restored
+	if err == nil || err.Error() != "even" {
+		t.Errorf("qux was not restored to its original behaviour after the panicking subtest, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+/*
+Package clock gives a test control over time.Now, time.Since, time.Sleep and time.NewTimer, the way
+the well-known clock-injection pattern does, but without requiring the code under test to accept a
+clock interface or dependency - it patches the stdlib functions directly via [testaroli.Override], the
+same mechanism the rest of this module uses everywhere else.
+
+Typical use, for code that backs off between retries:
+
+	func calculateBackoff(attempt int) time.Duration { ... }
+
+	func retry(n int, f func() error) error {
+	    var err error
+	    for i := 0; i < n; i++ {
+	        if err = f(); err == nil {
+	            return nil
+	        }
+	        time.Sleep(calculateBackoff(i))
+	    }
+	    return err
+	}
+
+	func TestRetryBacksOff(t *testing.T) {
+	    c := clock.FakeClock(testaroli.TestingContext(t), time.Unix(0, 0))
+	    go func() {
+	        for i := 0; i < 3; i++ {
+	            c.Advance(calculateBackoff(i)) // fires the pending time.Sleep synchronously
+	        }
+	    }()
+	    if err := retry(3, failingOp); err == nil {
+	        t.Error("expected retry to give up and return the last error")
+	    }
+	}
+*/
+package clock
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/qrdl/testaroli"
+)
+
+// Clock is a fake clock installed with [FakeClock]. Its zero value is not usable; only a *Clock
+// returned by FakeClock is.
+type Clock struct {
+	mu       sync.Mutex
+	now      time.Time
+	sleepers []sleeper
+}
+
+// sleeper is one pending time.Sleep call, parked on done until [Clock.Advance] reaches wake.
+type sleeper struct {
+	wake time.Time
+	done chan struct{}
+}
+
+/*
+FakeClock overrides time.Now, time.Since, time.Sleep and time.NewTimer for the lifetime of the test
+embedded in ctx (via [testaroli.Always], so every call for the rest of the test is served by the fake
+clock), starting the clock at start. Callers advance it explicitly with [Clock.Advance] - it never
+moves on its own.
+
+ctx must be created with [testaroli.TestingContext] or derived from one, the same as for
+[testaroli.Override].
+*/
+func FakeClock(ctx context.Context, start time.Time) *Clock {
+	c := &Clock{now: start}
+
+	testaroli.Override(ctx, time.Now, testaroli.Always, c.Now)
+	testaroli.Override(ctx, time.Since, testaroli.Always, func(t time.Time) time.Duration {
+		return c.Now().Sub(t)
+	})
+	testaroli.Override(ctx, time.Sleep, testaroli.Always, c.sleep)
+	testaroli.Override(ctx, time.NewTimer, testaroli.Always, c.newTimer)
+
+	return c
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// sleep parks the calling goroutine until a later [Clock.Advance] call reaches c.now+d.
+func (c *Clock) sleep(d time.Duration) {
+	<-c.park(d)
+}
+
+// newTimer is time.NewTimer's replacement: it returns a *time.Timer whose channel fires the same way
+// sleep unblocks, through a later [Clock.Advance].
+func (c *Clock) newTimer(d time.Duration) *time.Timer {
+	done := c.park(d)
+	ch := make(chan time.Time, 1)
+	go func() {
+		<-done
+		ch <- c.Now()
+	}()
+	return &time.Timer{C: ch}
+}
+
+// park registers a sleeper waking at c.now+d and returns the channel it will close when that happens.
+func (c *Clock) park(d time.Duration) chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	done := make(chan struct{})
+	if d <= 0 {
+		close(done)
+		return done
+	}
+	c.sleepers = append(c.sleepers, sleeper{wake: c.now.Add(d), done: done})
+	return done
+}
+
+// Advance moves the clock forward by d and synchronously fires every sleeper whose wake time has been
+// reached, in wake order, before returning - so the goroutine that called Advance can rely on every
+// time.Sleep/timer it unblocked having observed the new time already.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	var due []sleeper
+	remaining := c.sleepers[:0]
+	for _, s := range c.sleepers {
+		if !s.wake.After(c.now) {
+			due = append(due, s)
+		} else {
+			remaining = append(remaining, s)
+		}
+	}
+	c.sleepers = remaining
+	c.mu.Unlock()
+
+	slices.SortStableFunc(due, func(a, b sleeper) int { return a.wake.Compare(b.wake) })
+
+	for _, s := range due {
+		close(s.done)
+	}
+}
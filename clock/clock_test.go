@@ -0,0 +1,46 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qrdl/testaroli"
+)
+
+func TestFakeClockNow(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := FakeClock(testaroli.TestingContext(t), start)
+
+	if !time.Now().Equal(start) {
+		t.Errorf("expected time.Now() to return %v, got %v", start, time.Now())
+	}
+	c.Advance(time.Minute)
+	if !time.Now().Equal(start.Add(time.Minute)) {
+		t.Errorf("expected time.Now() to reflect Advance, got %v", time.Now())
+	}
+	if time.Since(start) != time.Minute {
+		t.Errorf("expected time.Since(start) to be %v, got %v", time.Minute, time.Since(start))
+	}
+}
+
+func TestFakeClockSleepFiresOnAdvance(t *testing.T) {
+	c := FakeClock(testaroli.TestingContext(t), time.Unix(0, 0))
+
+	sleeping := make(chan struct{})
+	woke := make(chan struct{})
+	go func() {
+		close(sleeping)
+		time.Sleep(time.Second)
+		close(woke)
+	}()
+	<-sleeping
+
+	select {
+	case <-woke:
+		t.Fatal("time.Sleep returned before Advance reached its wake time")
+	default:
+	}
+
+	c.Advance(time.Second)
+	<-woke // Advance fires due sleepers synchronously, so this never blocks for long
+}
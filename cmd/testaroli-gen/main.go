@@ -0,0 +1,100 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+Command testaroli-gen generates strongly-typed expecter wrappers for [Override], in the style of
+mockery's "with-expecter" generator. It should be invoked as `go generate` directive, e.g.
+
+	//go:generate go run github.com/qrdl/testaroli/cmd/testaroli-gen -out foo_testaroli.go bar.Foo (*bar.Baz).Qux
+
+For every target `pkg.Foo(a int, b string) error` it emits an
+`ExpectFoo(ctx context.Context, count int, a int, b string) error` wrapper that calls
+`Override(ctx, pkg.Foo, count, mock)(a, b)` with a mock calling `Expectation().CheckArgs(a, b)`, and
+returns what that call returns; for methods `(*T).Bar` it emits `ExpectTBar(ctx, count, recv *T, ...)`. The generated file is guarded by the `testaroli:expecter` build tag, so it
+is excluded from non-test builds unless the caller opts in.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func main() {
+	var (
+		out    = flag.String("out", "", "output file (required)")
+		pkg    = flag.String("pkg", ".", "import path or directory of the package that declares the targets")
+		config = flag.String("config", "", "file with one target identifier per line, in addition to any given on the command line")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s -out FILE [-pkg IMPORTPATH] [-config FILE] target ...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *out == "" {
+		fmt.Fprintln(os.Stderr, "testaroli-gen: -out is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	idents := flag.Args()
+	if *config != "" {
+		fromFile, err := readConfig(*config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "testaroli-gen: %v\n", err)
+			os.Exit(1)
+		}
+		idents = append(idents, fromFile...)
+	}
+	if len(idents) == 0 {
+		fmt.Fprintln(os.Stderr, "testaroli-gen: no targets given, pass them as arguments or via -config")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	targets, err := loadTargets(*pkg, idents)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testaroli-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	src, err := render(targets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "testaroli-gen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "testaroli-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// readConfig reads one target identifier per line, ignoring blank lines and '#' comments.
+func readConfig(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	var idents []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idents = append(idents, line)
+	}
+	return idents, nil
+}
@@ -0,0 +1,152 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+)
+
+// render emits the full contents of the generated *_testaroli.go file: the build-tag header followed
+// by one Expect<Name> wrapper per target, two wrappers (unrolled and slice-taking) for variadic targets.
+func render(targets []target) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by testaroli-gen. DO NOT EDIT.\n\n")
+	buf.WriteString("//go:build testaroli:expecter\n\n")
+	if len(targets) > 0 {
+		fmt.Fprintf(&buf, "package %s\n\n", targets[0].pkgName)
+	}
+	buf.WriteString("import (\n\t\"context\"\n\n\t. \"github.com/qrdl/testaroli\"\n)\n")
+
+	for _, t := range targets {
+		renderTarget(&buf, t, false)
+		if t.variadic != nil {
+			renderTarget(&buf, t, true)
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated source: %w", err)
+	}
+	return src, nil
+}
+
+/*
+renderTarget writes one Expect<Name> wrapper around Override(ctx, target, count, ...). It mirrors the
+existing "Override(...)(expectedArgs...)" idiom exactly, so the wrapper's return value and side effects
+are the same as calling Override by hand - the only difference is that its parameters are declared with
+the target's real types instead of being passed to CheckArgs as ...any, so a swapped or mistyped argument
+is a compile error at the call site instead of a test failure at run time.
+
+When asSlice is true and the target is variadic, the wrapper takes the variadic part as a single slice
+argument instead of unrolling it - the "slice-taking" variant for callers who already have a []T in hand.
+*/
+func renderTarget(buf *bytes.Buffer, t target, asSlice bool) {
+	wrapperName := "Expect" + t.funcName
+	if asSlice {
+		wrapperName += "Slice"
+	}
+
+	var sigParams, mockParams, callArgs, checkArgs []string
+	if t.recv != nil {
+		sigParams = append(sigParams, t.recv.name+" "+t.recv.typ)
+		callArgs = append(callArgs, t.recv.name)
+		checkArgs = append(checkArgs, t.recv.name)
+	}
+	for _, p := range t.params {
+		sigParams = append(sigParams, p.name+" "+p.typ)
+		callArgs = append(callArgs, p.name)
+		checkArgs = append(checkArgs, p.name)
+	}
+	// the mock always has the target's own (variadic) signature, whichever form the wrapper takes
+	mockParams = append(mockParams, sigParams...)
+	if t.variadic != nil {
+		mockParams = append(mockParams, t.variadic.name+" ..."+t.variadic.typ)
+		if asSlice {
+			sigParams = append(sigParams, t.variadic.name+" []"+t.variadic.typ)
+			callArgs = append(callArgs, t.variadic.name+"...")
+		} else {
+			sigParams = append(sigParams, t.variadic.name+" ..."+t.variadic.typ)
+			callArgs = append(callArgs, t.variadic.name+"...")
+		}
+		checkArgs = append(checkArgs, t.variadic.name)
+	}
+
+	fmt.Fprintf(buf, "\n/*\n%s overrides %s, expecting it to be called with the given arguments.\nSee [Override] for the meaning of ctx and count.\n*/\n",
+		wrapperName, t.selector)
+	fmt.Fprintf(buf, "func %s(ctx context.Context, count int%s) (%s) {\n", wrapperName, prefixedParams(sigParams), joinResultTypes(t.results))
+	fmt.Fprintf(buf, "\treturn Override(ctx, %s, count, func(%s) (%s) {\n",
+		t.selector, joinArgs(mockParams), joinResultTypes(t.results))
+	fmt.Fprintf(buf, "\t\tExpectation().CheckArgs(%s)\n", joinArgs(checkArgs))
+	fmt.Fprintf(buf, "\t\treturn %s\n", zeroResults(t.results))
+	buf.WriteString("\t})(")
+	buf.WriteString(joinArgs(callArgs))
+	buf.WriteString(")\n}\n")
+}
+
+func prefixedParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return ", " + joinArgs(params)
+}
+
+func joinArgs(args []string) string {
+	out := ""
+	for i, a := range args {
+		if i > 0 {
+			out += ", "
+		}
+		out += a
+	}
+	return out
+}
+
+func joinResultTypes(results []param) string {
+	types := make([]string, len(results))
+	for i, r := range results {
+		types[i] = r.typ
+	}
+	return joinArgs(types)
+}
+
+// zeroResults renders a bare "return" statement's values: the zero value of each result type. They are
+// never observed by callers of the overridden function while the mock is active with no further
+// behaviour attached - the point of CheckArgs here is purely to validate the call, same as a hand-written
+// mock that only calls Expectation().CheckArgs(...) and returns zero values.
+func zeroResults(results []param) string {
+	zeros := make([]string, len(results))
+	for i, r := range results {
+		zeros[i] = zeroOf(r.typ)
+	}
+	return joinArgs(zeros)
+}
+
+// zeroOf returns a literal producing the zero value of typ. It handles the common scalar cases directly;
+// anything else falls back to `*new(typ)`, which is valid Go for the zero value of any type.
+func zeroOf(typ string) string {
+	switch typ {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64":
+		return "0"
+	default:
+		return fmt.Sprintf("*new(%s)", typ)
+	}
+}
@@ -0,0 +1,116 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderFunction(t *testing.T) {
+	targets := []target{{
+		funcName: "Foo",
+		pkgName:  "bar",
+		selector: "bar.Foo",
+		params: []param{
+			{name: "a", typ: "int"},
+			{name: "b", typ: "string"},
+		},
+		results: []param{{typ: "error"}},
+	}}
+
+	src, err := render(targets)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	want := []string{
+		"//go:build testaroli:expecter",
+		"package bar",
+		"func ExpectFoo(ctx context.Context, count int, a int, b string) error {",
+		"Expectation().CheckArgs(a, b)",
+	}
+	for _, w := range want {
+		if !strings.Contains(string(src), w) {
+			t.Errorf("generated source missing %q:\n%s", w, src)
+		}
+	}
+}
+
+func TestRenderMethod(t *testing.T) {
+	targets := []target{{
+		funcName: "TBar",
+		pkgName:  "os",
+		selector: "(*os.File).Read",
+		recv:     &param{name: "recv", typ: "*os.File"},
+		params:   []param{{name: "b", typ: "[]byte"}},
+		results:  []param{{typ: "int"}, {typ: "error"}},
+	}}
+
+	src, err := render(targets)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	want := []string{
+		"func ExpectTBar(ctx context.Context, count int, recv *os.File, b []byte) (int, error) {",
+		"Expectation().CheckArgs(recv, b)",
+	}
+	for _, w := range want {
+		if !strings.Contains(string(src), w) {
+			t.Errorf("generated source missing %q:\n%s", w, src)
+		}
+	}
+}
+
+func TestRenderVariadicGeneratesBothVariants(t *testing.T) {
+	targets := []target{{
+		funcName: "Join",
+		pkgName:  "bar",
+		selector: "bar.Join",
+		params:   []param{{name: "sep", typ: "string"}},
+		variadic: &param{name: "items", typ: "string"},
+		results:  []param{{typ: "string"}},
+	}}
+
+	src, err := render(targets)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	want := []string{
+		"func ExpectJoin(ctx context.Context, count int, sep string, items ...string) string {",
+		"func ExpectJoinSlice(ctx context.Context, count int, sep string, items []string) string {",
+	}
+	for _, w := range want {
+		if !strings.Contains(string(src), w) {
+			t.Errorf("generated source missing %q:\n%s", w, src)
+		}
+	}
+}
+
+func TestZeroOf(t *testing.T) {
+	cases := map[string]string{
+		"string":   `""`,
+		"bool":     "false",
+		"int":      "0",
+		"error":    "*new(error)",
+		"[]byte":   "*new([]byte)",
+		"*os.File": "*new(*os.File)",
+	}
+	for typ, want := range cases {
+		if got := zeroOf(typ); got != want {
+			t.Errorf("zeroOf(%q) = %q, want %q", typ, got, want)
+		}
+	}
+}
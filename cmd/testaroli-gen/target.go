@@ -0,0 +1,212 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// param describes a single parameter (or result) of a target function/method.
+type param struct {
+	name string // synthesised if the original signature didn't name it, e.g. "arg2"
+	typ  string // string form of the type, qualified with its package's full import path, e.g. "*os.File"
+}
+
+// target is everything [render] needs to emit one Expect<Name> wrapper for a single
+// function or method identifier resolved by [loadTargets].
+type target struct {
+	funcName string  // "Foo", or "TBar" for method Bar on type T - used to build ExpectFoo/ExpectTBar
+	pkgName  string  // package the function/method is declared in, e.g. "bar"
+	selector string  // pkg.Foo, or (*pkg.T).Bar - how the target is referred to as the Override(...) argument
+	recv     *param  // non-nil for methods; receiver becomes the wrapper's first parameter
+	params   []param // non-variadic (or, for a variadic target, the fixed) parameters
+	variadic *param  // non-nil element type of the trailing variadic parameter, nil if not variadic
+	results  []param // return types, unnamed in the generated signature
+}
+
+// loadTargets resolves every identifier in idents (as found in the package at pkgPattern) to a target.
+// Identifiers are written the same way they'd be passed to [testaroli.Override]: "pkg.Foo" for a
+// function, "(*pkg.T).Bar" for a method.
+func loadTargets(pkgPattern string, idents []string) ([]target, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps}
+	pkgs, err := packages.Load(cfg, pkgPattern)
+	if err != nil {
+		return nil, fmt.Errorf("loading package %s: %w", pkgPattern, err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("package %s has errors", pkgPattern)
+	}
+
+	var targets []target
+	for _, ident := range idents {
+		t, err := resolveTarget(pkgs, ident)
+		if err != nil {
+			return nil, fmt.Errorf("target %q: %w", ident, err)
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// resolveTarget looks up ident - "pkg.Foo" or "(*pkg.T).Bar" - among the loaded packages and their
+// imports, and builds the [target] describing its signature.
+func resolveTarget(pkgs []*packages.Package, ident string) (target, error) {
+	recvType, funcName, err := splitIdent(ident)
+	if err != nil {
+		return target{}, err
+	}
+
+	obj := findFunc(pkgs, recvType, funcName)
+	if obj == nil {
+		return target{}, fmt.Errorf("function/method not found")
+	}
+	sig, ok := obj.Type().(*types.Signature)
+	if !ok {
+		return target{}, fmt.Errorf("%s is not a function", ident)
+	}
+
+	t := target{
+		pkgName:  obj.Pkg().Name(),
+		funcName: funcName,
+	}
+	if recv := sig.Recv(); recv != nil {
+		recvTypeName := recvDisplayName(recv.Type())
+		t.funcName = recvTypeName + funcName
+		t.selector = fmt.Sprintf("(%s).%s", typeString(recv.Type()), funcName)
+		t.recv = &param{name: "recv", typ: typeString(recv.Type())}
+	} else {
+		t.selector = fmt.Sprintf("%s.%s", obj.Pkg().Name(), funcName)
+	}
+
+	n := sig.Params().Len()
+	for i := 0; i < n; i++ {
+		p := sig.Params().At(i)
+		last := i == n-1
+		if last && sig.Variadic() {
+			elem := p.Type().(*types.Slice).Elem()
+			t.variadic = &param{name: paramName(p, i), typ: typeString(elem)}
+			continue
+		}
+		t.params = append(t.params, param{name: paramName(p, i), typ: typeString(p.Type())})
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		r := sig.Results().At(i)
+		t.results = append(t.results, param{typ: typeString(r.Type())})
+	}
+
+	return t, nil
+}
+
+// splitIdent splits an identifier of the form "pkg.Foo" or "(*pkg.T).Bar" into the receiver type
+// expression (empty for plain functions) and the function/method name.
+func splitIdent(ident string) (recvType, name string, err error) {
+	if strings.HasPrefix(ident, "(") {
+		close := strings.Index(ident, ")")
+		if close < 0 || !strings.HasPrefix(ident[close+1:], ".") {
+			return "", "", fmt.Errorf("malformed method identifier")
+		}
+		recvType = strings.TrimSpace(ident[1:close])
+		name = ident[close+2:]
+		if name == "" {
+			return "", "", fmt.Errorf("malformed method identifier")
+		}
+		return recvType, name, nil
+	}
+	i := strings.LastIndex(ident, ".")
+	if i < 0 {
+		return "", "", fmt.Errorf("expected pkg.Func or (*pkg.T).Method")
+	}
+	return "", ident[i+1:], nil
+}
+
+// findFunc searches pkgs and their imports for the function or method named funcName, optionally
+// declared on recvType (e.g. "*bar.Baz"); recvType == "" matches a plain function.
+func findFunc(pkgs []*packages.Package, recvType, funcName string) types.Object {
+	seen := map[*packages.Package]bool{}
+	var walk func(p *packages.Package) types.Object
+	walk = func(p *packages.Package) types.Object {
+		if p == nil || seen[p] {
+			return nil
+		}
+		seen[p] = true
+		if p.Types != nil {
+			if recvType == "" {
+				if obj := p.Types.Scope().Lookup(funcName); obj != nil {
+					if _, ok := obj.(*types.Func); ok {
+						return obj
+					}
+				}
+			} else if obj := lookupMethod(p, recvType, funcName); obj != nil {
+				return obj
+			}
+		}
+		for _, dep := range p.Imports {
+			if obj := walk(dep); obj != nil {
+				return obj
+			}
+		}
+		return nil
+	}
+	for _, p := range pkgs {
+		if obj := walk(p); obj != nil {
+			return obj
+		}
+	}
+	return nil
+}
+
+// lookupMethod finds method funcName declared on the named type matching recvType ("*T" or "T")
+// within package p.
+func lookupMethod(p *packages.Package, recvType, funcName string) types.Object {
+	typeName := strings.TrimPrefix(strings.TrimPrefix(recvType, "*"), p.Types.Name()+".")
+	obj := p.Types.Scope().Lookup(typeName)
+	named, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	nt, ok := named.Type().(*types.Named)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < nt.NumMethods(); i++ {
+		if m := nt.Method(i); m.Name() == funcName {
+			return m
+		}
+	}
+	return nil
+}
+
+func recvDisplayName(t types.Type) string {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+func typeString(t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(nil))
+}
+
+func paramName(p *types.Var, i int) string {
+	if p.Name() != "" {
+		return p.Name()
+	}
+	return fmt.Sprintf("arg%d", i+1)
+}
@@ -0,0 +1,67 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+package testaroli
+
+import (
+	"context"
+	"testing"
+)
+
+/*
+Controller scopes a set of overrides to one test or subtest, analogous to gomock.Controller, for callers
+who'd rather hold an explicit handle than pass a bare context around. It is a thin wrapper around
+[TestingContext]: the concurrency safety it offers comes entirely from the underlying per-goroutine
+[expectChain] (keyed off the calling goroutine, see [currentChain]) and the [patchMu] lock serializing
+prologue writes - a Controller built in one t.Run subtest or t.Parallel() goroutine never shares bookkeeping
+with one built in another.
+
+Patching the same function from two Controllers at once is still unsafe, since both would be writing to
+that function's executable bytes concurrently; only Controllers that override disjoint functions are safe
+to use from parallel subtests.
+*/
+type Controller struct {
+	ctx context.Context
+}
+
+/*
+NewController returns a Controller scoped to t, analogous to gomock.NewController(t). Pass the result of
+[Controller.Context] as the ctx argument to [Override]/[Group] for overrides that belong to this
+Controller:
+
+	ctrl := NewController(t)
+	Override(ctrl.Context(), foo, Once, func(a int) { Expectation().CheckArgs(a) })(42)
+
+Like [TestingContext], the first override registered through ctrl.Context() registers a
+[testing.T.Cleanup] handler that checks [ExpectationsWereMet] and restores original prologues
+automatically, so calling [Controller.ExpectationsWereMet] by hand is optional.
+*/
+func NewController(t *testing.T) *Controller {
+	return &Controller{ctx: TestingContext(t)}
+}
+
+// Context returns the context scoped to ctrl, to be passed as the ctx argument to [Override]/[Group].
+func (ctrl *Controller) Context() context.Context {
+	return ctrl.ctx
+}
+
+/*
+ExpectationsWereMet is [ExpectationsWereMet] for ctrl's own goroutine. Calling it is optional - the
+[testing.T.Cleanup] handler registered via [Controller.Context]/[TestingContext] already does this when
+the test finishes - but it's available for parity with other mocking libraries' Controller.Finish()-style
+APIs, and for asserting expectations were met partway through a longer test.
+*/
+func (ctrl *Controller) ExpectationsWereMet() error {
+	return ExpectationsWereMet()
+}
@@ -0,0 +1,42 @@
+package testaroli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestControllerOverrideAndExpectationsWereMet(t *testing.T) {
+	ctrl := NewController(t)
+	dummy := errors.New("dummy")
+
+	Override(ctrl.Context(), qux, Once, func(err error) error {
+		Expectation()
+		return dummy
+	})(nil)
+
+	testError(t, dummy, qux(nil))
+	testError(t, nil, ctrl.ExpectationsWereMet())
+}
+
+func TestControllersInParallelSubtestsDontRace(t *testing.T) {
+	t.Run("group", func(t *testing.T) {
+		t.Run("bar", func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(t)
+			Override(ctrl.Context(), bar, Once, func(i int) error {
+				Expectation().CheckArgs(i)
+				return nil
+			})(1)
+			testError(t, nil, bar(1))
+		})
+		t.Run("baz", func(t *testing.T) {
+			t.Parallel()
+			ctrl := NewController(t)
+			Override(ctrl.Context(), baz, Once, func(i int) error {
+				Expectation().CheckArgs(i)
+				return nil
+			})(2)
+			testError(t, nil, baz(2))
+		})
+	})
+}
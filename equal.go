@@ -5,6 +5,42 @@ import (
 	"reflect"
 )
 
+// DiffKind categorizes why two values compared unequal, so a [Formatter] can phrase each case
+// differently instead of working from a single generic message.
+type DiffKind int
+
+const (
+	DiffValueMismatch  DiffKind = iota // leaf values of the same type and shape differ
+	DiffTypeMismatch                   // actual and expected are of different types
+	DiffLengthMismatch                 // a map, slice or array differ in length/element count
+)
+
+// Diff describes where and how two compared values first differed. Path is the chain of struct
+// fields, slice/array indices and map keys leading to the differing value, e.g. `.Balance`,
+// `[2]` or `["222"]`, empty at the top level where a and e are compared directly.
+type Diff struct {
+	Path     string
+	Actual   any
+	Expected any
+	Kind     DiffKind
+}
+
+// withPathPrefix prepends prefix to d's Path in place and returns d, used as equal() unwinds back
+// up the recursion - each level adds the step (field name, index, key) that led to it.
+func (d *Diff) withPathPrefix(prefix string) *Diff {
+	d.Path = prefix + d.Path
+	return d
+}
+
+// safeInterface returns v's value as an any, falling back to its formatted string when v can't be
+// interfaced (e.g. an unexported struct field), so building a [Diff] never panics.
+func safeInterface(v reflect.Value) any {
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 // standard reflect.Value.Equal has several issues:
 // - it compares pointers only as addresses
 // - it doesn't compare maps
@@ -12,7 +48,7 @@ import (
 // - it doesn't explain what exactly has failed
 // - it panics
 // so I've rolled my own, based on reflect's implementation
-func equal(a, e reflect.Value) (bool, string) {
+func equal(a, e reflect.Value) (bool, *Diff) {
 	if a.Kind() == reflect.Interface {
 		a = a.Elem()
 	}
@@ -21,112 +57,113 @@ func equal(a, e reflect.Value) (bool, string) {
 	}
 
 	if !a.IsValid() || !e.IsValid() {
-		return a.IsValid() == e.IsValid(), "cannot compare invalid value with valid one"
+		if a.IsValid() == e.IsValid() {
+			return true, nil
+		}
+		return false, &Diff{Actual: safeInterface(a), Expected: safeInterface(e), Kind: DiffValueMismatch}
 	}
 
 	if a.Kind() != e.Kind() || a.Type() != e.Type() {
-		return false, fmt.Sprintf("actual type '%s' differs from expected '%s'", a.Type(), e.Type())
+		return false, &Diff{Actual: a.Type().String(), Expected: e.Type().String(), Kind: DiffTypeMismatch}
 	}
 
 	switch a.Kind() {
 	case reflect.Bool:
-		return a.Bool() == e.Bool(), ""
+		return a.Bool() == e.Bool(), valueDiff(a, e, a.Bool() == e.Bool())
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return a.Int() == e.Int(), ""
+		return a.Int() == e.Int(), valueDiff(a, e, a.Int() == e.Int())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		return a.Uint() == e.Uint(), ""
+		return a.Uint() == e.Uint(), valueDiff(a, e, a.Uint() == e.Uint())
 	case reflect.Float32, reflect.Float64:
-		return a.Float() == e.Float(), ""
+		return a.Float() == e.Float(), valueDiff(a, e, a.Float() == e.Float())
 	case reflect.Complex64, reflect.Complex128:
-		return a.Complex() == e.Complex(), ""
+		return a.Complex() == e.Complex(), valueDiff(a, e, a.Complex() == e.Complex())
 	case reflect.String:
-		return a.String() == e.String(), ""
+		return a.String() == e.String(), valueDiff(a, e, a.String() == e.String())
 	case reflect.Chan:
-		return a.Pointer() == e.Pointer(), ""
+		return a.Pointer() == e.Pointer(), valueDiff(a, e, a.Pointer() == e.Pointer())
 	case reflect.Pointer, reflect.UnsafePointer:
 		if a.Pointer() == e.Pointer() {
-			return true, ""
+			return true, nil
 		}
-		res, str := equal(reflect.Indirect(a), reflect.Indirect(e))
-		if !res && str == "" {
-			str = fmt.Sprintf("actual value '%v' differs from expected '%v'", reflect.Indirect(a), reflect.Indirect(e))
+		res, diff := equal(reflect.Indirect(a), reflect.Indirect(e))
+		if !res && diff == nil {
+			diff = valueDiff(reflect.Indirect(a), reflect.Indirect(e), false)
 		}
-		return res, str
+		return res, diff
 	case reflect.Array:
 		// u and v have the same type so they have the same length
 		vl := a.Len()
-		if vl == 0 {
-			return true, ""
-		}
 		for i := 0; i < vl; i++ {
-			res, str := equal(a.Index(i), e.Index(i))
+			res, diff := equal(a.Index(i), e.Index(i))
 			if !res {
-				if str == "" {
-					str = fmt.Sprintf("actual value '%v' differs from expected '%v'",
-						a.Index(i), e.Index(i))
+				if diff == nil {
+					diff = valueDiff(a.Index(i), e.Index(i), false)
 				}
-				return false, fmt.Sprintf("array elem %d: %s", i, str)
+				return false, diff.withPathPrefix(fmt.Sprintf("[%d]", i))
 			}
 		}
-		return true, ""
+		return true, nil
 	case reflect.Struct:
 		// u and v have the same type so they have the same fields
 		nf := a.NumField()
 		for i := 0; i < nf; i++ {
-			res, str := equal(a.Field(i), e.Field(i))
+			res, diff := equal(a.Field(i), e.Field(i))
 			if !res {
-				if str == "" {
-					str = fmt.Sprintf("actual value '%v' differs from expected '%v'",
-						a.Field(i), e.Field(i))
+				if diff == nil {
+					diff = valueDiff(a.Field(i), e.Field(i), false)
 				}
-				return false, fmt.Sprintf("struct field '%s': %s", a.Type().Field(i).Name, str)
+				return false, diff.withPathPrefix("." + a.Type().Field(i).Name)
 			}
 		}
-		return true, ""
+		return true, nil
 	case reflect.Map:
 		if a.Pointer() == e.Pointer() {
-			return true, ""
+			return true, nil
 		}
 		keys := a.MapKeys()
 		if len(keys) != len(e.MapKeys()) {
-			return false, "map lengths differ"
+			return false, &Diff{Kind: DiffLengthMismatch, Actual: len(keys), Expected: len(e.MapKeys())}
 		}
 		for _, k := range keys {
-			res, str := equal(a.MapIndex(k), e.MapIndex(k))
+			res, diff := equal(a.MapIndex(k), e.MapIndex(k))
 			if !res {
-				if str == "" {
-					str = fmt.Sprintf("actual value '%v' differs from expected '%v'",
-						a.MapIndex(k), e.MapIndex(k))
+				if diff == nil {
+					diff = valueDiff(a.MapIndex(k), e.MapIndex(k), false)
 				}
-				return false, fmt.Sprintf("map value for key '%v': %s", k, str)
+				return false, diff.withPathPrefix(fmt.Sprintf("[%#v]", k))
 			}
 		}
-		return true, ""
+		return true, nil
 	case reflect.Func:
-		return a.Pointer() == e.Pointer(), ""
 		// function can be equal only to itself
+		return a.Pointer() == e.Pointer(), valueDiff(a, e, a.Pointer() == e.Pointer())
 	case reflect.Slice:
 		if a.Pointer() == e.Pointer() {
-			return true, ""
+			return true, nil
 		}
 		vl := a.Len()
 		if vl != e.Len() {
-			return false, "slice lengths differ"
-		}
-		if vl == 0 {
-			return true, ""
+			return false, &Diff{Kind: DiffLengthMismatch, Actual: vl, Expected: e.Len()}
 		}
 		for i := 0; i < vl; i++ {
-			res, str := equal(a.Index(i), e.Index(i))
+			res, diff := equal(a.Index(i), e.Index(i))
 			if !res {
-				if str == "" {
-					str = fmt.Sprintf("actual value '%v' differs from expected '%v'",
-						a.Index(i), e.Index(i))
+				if diff == nil {
+					diff = valueDiff(a.Index(i), e.Index(i), false)
 				}
-				return false, fmt.Sprintf("slice elem %d: %s", i, str)
+				return false, diff.withPathPrefix(fmt.Sprintf("[%d]", i))
 			}
 		}
-		return true, ""
+		return true, nil
+	}
+	return false, &Diff{Kind: DiffValueMismatch} // should never happen
+}
+
+// valueDiff returns a leaf [Diff] for a and e when they're not equal, nil otherwise.
+func valueDiff(a, e reflect.Value, eq bool) *Diff {
+	if eq {
+		return nil
 	}
-	return false, "invalid variable Kind" // should never happen
+	return &Diff{Actual: safeInterface(a), Expected: safeInterface(e), Kind: DiffValueMismatch}
 }
@@ -33,9 +33,42 @@ type Expect struct {
 	actCount    int
 	mockAddr    unsafe.Pointer
 	orgAddr     unsafe.Pointer
+	orgType     reflect.Type // org's function type, needed to (re)build a [GoroutineLocal] dispatcher
 	args        []reflect.Value
 	orgName     string
+	orgVariadic bool // true when org's last parameter is a variadic ...T, set by Override
 	orgPrologue []byte
+	installed   bool // true once install() has patched the prologue or registered with the dispatcher
+	prereqs     []*Expect
+	card        *Cardinality // set by OverrideCardinality, nil for exact-count expectations
+}
+
+// reset restores org's original prologue for e, going through the [GoroutineLocal] dispatcher's own
+// bookkeeping instead of a plain [reset] when e was registered with that scope.
+func (e *Expect) reset() {
+	if scopeOf(e.ctx) == GoroutineLocal {
+		unregisterScoped(e.orgAddr)
+		return
+	}
+	withPatchLock(func() {
+		reset(e.orgAddr, e.orgPrologue)
+	})
+}
+
+// install patches org's prologue for e - or registers e's mock with the shared dispatcher, for a
+// [GoroutineLocal] override - the first time e becomes reachable.
+func (e *Expect) install() {
+	if e.installed {
+		return
+	}
+	e.installed = true
+	if scopeOf(e.ctx) == GoroutineLocal {
+		registerScoped(e.orgType, e.orgAddr, e.mockAddr)
+		return
+	}
+	withPatchLock(func() {
+		e.orgPrologue = override(e.orgAddr, e.mockAddr) // call arch-specific function
+	})
 }
 
 /*
@@ -53,40 +86,137 @@ func Expectation() *Expect {
 	}
 	entry := runtime.FuncForPC(pc).Entry()
 
+	c := currentChainForDispatch()
+
+	// make sure we have called expected function, skipping past any unconsumed Maybe group that the
+	// call bypasses in favour of an override further down the chain
 	var expect *Expect
-	var order int
-	// make sure we have called expected function
-	for i, e := range expectations {
-		if uintptr(e.mockAddr) == entry {
-			// must be either Always or first on non-Always
-			if e.expCount == Always || numLeadingAlways() == i {
-				expect = e
-				order = i
-				break
-			}
-			panic("unexpected function call") // should never happen
+	for {
+		hg := headGroup(c)
+		e, g := findExpectation(c, entry)
+		if e == nil {
+			panic("unexpected function call - not from mock function")
+		}
+		// must be either Always, a member of the group currently at the head of the chain, or reached
+		// by skipping over a head group that is entirely optional
+		if e.expCount == Always || g == hg {
+			expect = e
+			break
+		}
+		if hg != nil && allMaybe(hg) {
+			skipGroup(c, hg) // nothing in the head group was required, let the chain move on
+			continue
 		}
+		panic("unexpected function call") // should never happen
 	}
-	if expect == nil {
-		panic("unexpected function call - not from mock function")
+
+	for _, p := range expect.prereqs {
+		if !prereqMet(p) {
+			panic(fmt.Sprintf("call %s invoked before prerequisite %s was fully consumed", expect.orgName, p.orgName))
+		}
 	}
 
 	expect.actCount++
-	if expect.actCount == expect.expCount && !(expect.expCount == Unlimited || expect.expCount == Always) {
-		reset(expect.orgAddr, expect.orgPrologue)
-		expectations = slices.Delete(expectations, order, order+1) // remove from expected chain
-		overrideNextInChain()
+	advance := expect.actCount == expect.expCount &&
+		!(expect.expCount == Unlimited || expect.expCount == Always || expect.expCount == Maybe)
+	if expect.card != nil {
+		// a bounded Cardinality advances the chain once its upper bound is reached, same as an exact
+		// count would; an unbounded one (card.max < 0) behaves like Unlimited and never advances itself
+		advance = expect.card.max >= 0 && expect.actCount >= expect.card.max
+	}
+	if advance {
+		expect.reset()
+		removeExpectation(c, expect) // remove from expected chain, dropping its group once it's empty
+		overrideNextInChain(c)
 	}
 
 	return expect
 }
 
-func overrideNextInChain() {
-	next := numLeadingAlways()
-	if next < len(expectations) {
-		expectations[next].orgPrologue = override( // call arch-specific function
-			expectations[next].orgAddr,
-			expectations[next].mockAddr)
+// prereqMet reports whether a prerequisite [Expect] has been fully consumed, i.e. it was called the
+// expected number of times. [Unlimited] and [Always] prerequisites, which never run out on their own,
+// are considered met once called at least once; [Maybe] prerequisites, which may never be called at all,
+// are always considered met.
+func prereqMet(e *Expect) bool {
+	if e.card != nil {
+		return e.actCount >= e.card.min
+	}
+	switch e.expCount {
+	case Unlimited, Always:
+		return e.actCount > 0
+	case Maybe:
+		return true
+	default:
+		return e.actCount >= e.expCount
+	}
+}
+
+/*
+NotBefore records that e must not fire until every expectation in prereqs has been fully consumed.
+[Expectation] panics with a descriptive error if the mock is called while a prerequisite is still
+outstanding. See also the package-level [InOrder], which wires NotBefore across a whole slice of
+expectations or groups.
+*/
+func (e *Expect) NotBefore(prereqs ...*Expect) *Expect {
+	e.prereqs = append(e.prereqs, prereqs...)
+	return e
+}
+
+// After is [Expect.NotBefore] under the name used by other Go mock frameworks' call-ordering APIs -
+// e.After(other) reads the same as e.NotBefore(other).
+func (e *Expect) After(prereqs ...*Expect) *Expect {
+	return e.NotBefore(prereqs...)
+}
+
+// findExpectation returns the expectation registered for entry (the mock's function entry point) along
+// with the group that owns it, or nil, nil if entry doesn't belong to any registered mock.
+func findExpectation(c *expectChain, entry uintptr) (*Expect, *OverrideGroup) {
+	for _, g := range c.groups {
+		for _, e := range g.expectations {
+			if uintptr(e.mockAddr) == entry {
+				return e, g
+			}
+		}
+	}
+	return nil, nil
+}
+
+// skipGroup restores the original function for every member of g, an all-[Maybe] group that wasn't
+// called before the test moved on to the next override, and drops it from the chain so the following
+// group can take its place at the head.
+func skipGroup(c *expectChain, g *OverrideGroup) {
+	for _, e := range g.expectations {
+		e.reset()
+	}
+	if i := slices.Index(c.groups, g); i >= 0 {
+		c.groups = slices.Delete(c.groups, i, i+1)
+	}
+	overrideNextInChain(c)
+}
+
+// removeExpectation deletes e from the group that owns it, dropping the group itself from the chain
+// once its last member has been consumed.
+func removeExpectation(c *expectChain, e *Expect) {
+	for gi, g := range c.groups {
+		if i := slices.Index(g.expectations, e); i >= 0 {
+			g.expectations = slices.Delete(g.expectations, i, i+1)
+			if len(g.expectations) == 0 {
+				c.groups = slices.Delete(c.groups, gi, gi+1)
+			}
+			return
+		}
+	}
+}
+
+// overrideNextInChain patches every not-yet-patched override in the group that is now at the head of
+// the chain, i.e. the group following the one that was just fully consumed.
+func overrideNextInChain(c *expectChain) {
+	g := headGroup(c)
+	if g == nil {
+		return
+	}
+	for _, e := range g.expectations {
+		e.install()
 	}
 }
 
@@ -115,6 +245,9 @@ func (e Expect) RunNumber() int {
 /*
 Expect sets the expected argument values, that can be later checked with [Expect.CheckArgs].
 See [Override] for better way (with compile-time type checks) of setting expected values.
+
+Any element of args may be a [Matcher] (e.g. [Any], [Eq], [Len]) instead of a literal value, in
+which case [Expect.CheckArgs] calls its Matches method instead of comparing values directly.
 */
 func (e *Expect) Expect(args ...any) *Expect {
 	expArgs := make([]reflect.Value, len(args))
@@ -129,6 +262,16 @@ func (e *Expect) Expect(args ...any) *Expect {
 /*
 CheckArgs checks if actual values match the expected ones.
 
+If org is variadic, CheckArgs accepts the variadic tail either spread (one [Expect.Expect] value per
+actual argument, same as a non-variadic call) or collapsed into a single expected slice covering every
+trailing argument - both forms are checked the same way, so a mock doesn't have to pick one over the
+other just to match CheckArgs's own argument count. See also [Expect.CheckVariadic] for matching the
+variadic tail against one [Matcher] per argument without setting expected values up front, and [Rest]
+for matching "all remaining" within a normal [Expect.Expect] call.
+
+Every call to CheckArgs is journaled to its chain's call log, whether or not the args matched - see
+[Calls] and [AllCalls].
+
 Please note that when reporting differences, this function always use zero-based
 numbering - for array/slice elements, function arguments and run numbers, e.g. first
 call (if function was overridden for several calls) is called `run 0`
@@ -138,22 +281,54 @@ func (e Expect) CheckArgs(args ...any) {
 	t := e.Testing()
 	t.Helper()
 
-	if len(args) != len(e.args) {
-		if len(e.args) == 0 {
+	e.record(args)
+
+	expArgs := e.args
+	if n := len(expArgs); n > 0 {
+		if rm, ok := restMatcherOf(expArgs[n-1]); ok {
+			if len(args) < n-1 {
+				t.Errorf("actual arg count %d is fewer than the %d fixed args expected", len(args), n-1)
+				return
+			}
+			expArgs = append(append([]reflect.Value{}, expArgs[:n-1]...), make([]reflect.Value, len(args)-(n-1))...)
+			for i := n - 1; i < len(args); i++ {
+				expArgs[i] = reflect.ValueOf(rm.inner)
+			}
+		} else if e.orgVariadic && len(args) != n {
+			args, expArgs = normalizeVariadic(args, expArgs)
+		}
+	}
+
+	if len(args) != len(expArgs) {
+		if len(expArgs) == 0 {
 			t.Errorf("no extected args set")
 		} else {
-			t.Errorf("actual arg count %d doesn't match expected %d", len(args), len(e.args))
+			t.Errorf("actual arg count %d doesn't match expected %d", len(args), len(expArgs))
 		}
 		return
 	}
 
 	for i, a := range args {
 		actualArg := reflect.ValueOf(a)
-		expectedArg := e.args[i]
+		expectedArg := expArgs[i]
+		if expectedArg.IsValid() && expectedArg.CanInterface() {
+			if m, ok := expectedArg.Interface().(Matcher); ok {
+				if !m.Matches(a) {
+					if e.expCount > 1 || e.expCount == Unlimited || e.expCount == Always || e.expCount == Maybe || e.card != nil {
+						t.Errorf("arg %d on the run %d: actual value '%v' doesn't match %s",
+							i, e.actCount-1, a, m.String())
+					} else {
+						t.Errorf("arg %d: actual value '%v' doesn't match %s", i, a, m.String())
+					}
+					return
+				}
+				continue
+			}
+		}
 		if a == nil {
 			// process situations when Expect(nil) is called
 			if expectedArg.IsValid() && (!isNillable(expectedArg) || !expectedArg.IsNil()) {
-				if e.expCount > 1 || e.expCount == Unlimited || e.expCount == Always {
+				if e.expCount > 1 || e.expCount == Unlimited || e.expCount == Always || e.expCount == Maybe || e.card != nil {
 					t.Errorf(
 						"arg %d on the run %d actual value is nil while non-nil is expected",
 						i,
@@ -168,14 +343,10 @@ func (e Expect) CheckArgs(args ...any) {
 			}
 			continue
 		}
-		res, msg := equal(actualArg, expectedArg)
+		res, diff := equal(actualArg, expectedArg)
 		if !res {
-			if msg == "" {
-				msg = fmt.Sprintf("actual value '%v' differs from expected '%v'",
-					actualArg,
-					expectedArg)
-			}
-			if e.expCount > 1 || e.expCount == Unlimited || e.expCount == Always {
+			msg := formatterOf(e.ctx).Format(diff)
+			if e.expCount > 1 || e.expCount == Unlimited || e.expCount == Always || e.expCount == Maybe || e.card != nil {
 				t.Errorf("arg %d on the run %d: %s",
 					i+1,
 					e.actCount-1, // 0-based
@@ -188,6 +359,72 @@ func (e Expect) CheckArgs(args ...any) {
 	}
 }
 
+// restMatcherOf reports whether v holds a [Rest] matcher, unwrapping it so CheckArgs can apply its
+// inner [Matcher] to however many actual trailing arguments there turn out to be.
+func restMatcherOf(v reflect.Value) (restMatcher, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return restMatcher{}, false
+	}
+	rm, ok := v.Interface().(restMatcher)
+	return rm, ok
+}
+
+// normalizeVariadic reconciles args against expArgs for a variadic org when the two disagree on
+// whether the variadic tail was spread (one value per argument) or collapsed into a single slice.
+// Whichever side collapsed it, that side's tail is spread out to line up element-for-element with the
+// other side before the normal comparison runs.
+func normalizeVariadic(args []any, expArgs []reflect.Value) ([]any, []reflect.Value) {
+	n := len(expArgs)
+	if n == 0 {
+		return args, expArgs
+	}
+	if len(args) > n {
+		// expected side may have collapsed the tail into one slice; spread it to match args
+		last := expArgs[n-1]
+		if last.IsValid() && last.Kind() == reflect.Slice {
+			spread := make([]reflect.Value, last.Len())
+			for i := 0; i < last.Len(); i++ {
+				spread[i] = last.Index(i)
+			}
+			return args, append(append([]reflect.Value{}, expArgs[:n-1]...), spread...)
+		}
+	} else if len(args) > 0 && len(args) < n {
+		// actual side may have collapsed the tail into one slice; spread it to match expArgs
+		lastArg := reflect.ValueOf(args[len(args)-1])
+		if lastArg.IsValid() && lastArg.Kind() == reflect.Slice {
+			spread := make([]any, 0, len(args)-1+lastArg.Len())
+			spread = append(spread, args[:len(args)-1]...)
+			for i := 0; i < lastArg.Len(); i++ {
+				spread = append(spread, lastArg.Index(i).Interface())
+			}
+			return spread, expArgs
+		}
+	}
+	return args, expArgs
+}
+
+/*
+CheckVariadic checks actual's trailing (variadic) arguments against matchers, one matcher per argument,
+without first having to set expected values with [Expect.Expect] - use it when every trailing argument
+needs its own [Matcher], as in:
+
+	Expectation().CheckVariadic(args, Any(), InRange(0, 10))
+*/
+func (e Expect) CheckVariadic(actual []any, matchers ...Matcher) {
+	t := e.Testing()
+	t.Helper()
+
+	if len(actual) != len(matchers) {
+		t.Errorf("actual variadic arg count %d doesn't match matcher count %d", len(actual), len(matchers))
+		return
+	}
+	for i, a := range actual {
+		if !matchers[i].Matches(a) {
+			t.Errorf("variadic arg %d: actual value '%v' doesn't match %s", i, a, matchers[i].String())
+		}
+	}
+}
+
 /*
 Context returns [context.Context], passed to [Override] function.
 */
@@ -0,0 +1,162 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+equal() used to collapse every mismatch into a single "actual value 'X' differs from expected 'Y'"
+string, which is unreadable once X or Y is a large struct, map or slice - [Expect.CheckArgs] only
+reports the first field/key/index that differs, but that one value can still be a paragraph. equal()
+now builds a [Diff] describing just that leaf (its path within the compared value, plus the two leaf
+values), and a [Formatter] turns it into text. [DefaultFormatter] is [DiffFormatter], a
+unified-diff-style `path: -actual +expected` line with long values truncated; swap in
+[LegacyFormatter] for the same one-line shape this package always had, [ColorFormatter] for the diff
+line with ANSI colour, or [JSONFormatter] to pipe the raw [Diff] into another tool. [WithFormatter]
+attaches the choice to a context.
+
+There's no automatic TTY detection for [ColorFormatter] - doing that reliably cross-platform needs
+either a new dependency this module doesn't otherwise have, or an untestable heuristic, so a test
+that wants colour opts in explicitly via WithFormatter. There's likewise no go-cmp-backed Formatter
+bundled here, since this module has no go-cmp dependency; a caller that wants one can implement
+[Formatter] themselves - it's a single method.
+*/
+package testaroli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Formatter turns a [Diff] into the message [Expect.CheckArgs] reports via t.Errorf.
+type Formatter interface {
+	Format(d *Diff) string
+}
+
+// formatterKey is an unexported context key, the same pattern [scopeContextKey] uses, so it can't
+// collide with a key any other package (or context value) might pick.
+type formatterKey int
+
+const formatterContextKey = formatterKey(1)
+
+// WithFormatter attaches f to ctx, so any [Expect] created from ctx (or a context derived from it)
+// reports mismatches through f instead of [DefaultFormatter].
+func WithFormatter(ctx context.Context, f Formatter) context.Context {
+	return context.WithValue(ctx, formatterContextKey, f)
+}
+
+// formatterOf returns ctx's attached [Formatter], or [DefaultFormatter] if none was attached.
+func formatterOf(ctx context.Context) Formatter {
+	if f, ok := ctx.Value(formatterContextKey).(Formatter); ok && f != nil {
+		return f
+	}
+	return DefaultFormatter
+}
+
+// legacyFormatter reproduces the single-line message equal() itself used to return, kept as an
+// explicit opt-in for any test that depends on matching it exactly.
+type legacyFormatter struct{}
+
+func (legacyFormatter) Format(d *Diff) string {
+	switch d.Kind {
+	case DiffTypeMismatch:
+		return fmt.Sprintf("actual type '%v' differs from expected '%v'", d.Actual, d.Expected)
+	default:
+		return fmt.Sprintf("actual value '%v' differs from expected '%v'", d.Actual, d.Expected)
+	}
+}
+
+// LegacyFormatter reports a mismatch the way this package always has, ignoring Path - pass it to
+// [WithFormatter] for exact backward compatibility with code that parses CheckArgs's error text.
+var LegacyFormatter Formatter = legacyFormatter{}
+
+// maxFormattedValueLen truncates an overly long formatted value so one differing field doesn't turn
+// a CheckArgs failure into a screen-filling wall of text.
+const maxFormattedValueLen = 120
+
+func truncateFormatted(v any) string {
+	s := fmt.Sprintf("%v", v)
+	if len(s) <= maxFormattedValueLen {
+		return s
+	}
+	return s[:maxFormattedValueLen] + "..."
+}
+
+func diffPath(d *Diff) string {
+	if d.Path == "" {
+		return "value"
+	}
+	return d.Path
+}
+
+// diffFormatter renders a Diff as a unified-diff-style `path: -actual +expected` line.
+type diffFormatter struct{}
+
+func (diffFormatter) Format(d *Diff) string {
+	switch d.Kind {
+	case DiffTypeMismatch:
+		return fmt.Sprintf("%s: type differs, actual %v, expected %v", diffPath(d), d.Actual, d.Expected)
+	case DiffLengthMismatch:
+		return fmt.Sprintf("%s: length differs, actual %v, expected %v", diffPath(d), d.Actual, d.Expected)
+	default:
+		return fmt.Sprintf("%s: -%s +%s", diffPath(d), truncateFormatted(d.Actual), truncateFormatted(d.Expected))
+	}
+}
+
+// DiffFormatter renders a path-qualified, unified-diff-style report, e.g.
+// `["222"].Balance: -234.56 +235.79` - the long-form replacement for [LegacyFormatter] once a
+// mismatch is buried several fields deep in a struct, map or slice.
+var DiffFormatter Formatter = diffFormatter{}
+
+// DefaultFormatter is the [Formatter] used when a context has none attached via [WithFormatter].
+// It is [DiffFormatter]; pass [LegacyFormatter] to WithFormatter for the old single-line wording.
+var DefaultFormatter Formatter = DiffFormatter
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiReset = "\x1b[0m"
+)
+
+// colorFormatter is [DiffFormatter] with the actual/expected values wrapped in ANSI red/green.
+type colorFormatter struct{}
+
+func (colorFormatter) Format(d *Diff) string {
+	switch d.Kind {
+	case DiffTypeMismatch, DiffLengthMismatch:
+		return diffFormatter{}.Format(d)
+	default:
+		return fmt.Sprintf("%s: %s-%s%s %s+%s%s",
+			diffPath(d),
+			ansiRed, truncateFormatted(d.Actual), ansiReset,
+			ansiGreen, truncateFormatted(d.Expected), ansiReset)
+	}
+}
+
+// ColorFormatter is [DiffFormatter] with the actual/expected values coloured red/green via ANSI
+// escape codes - pass it to [WithFormatter] explicitly for a terminal known to support them; see the
+// package doc for why this isn't detected automatically.
+var ColorFormatter Formatter = colorFormatter{}
+
+// jsonFormatter marshals the raw Diff, for a test harness that wants to parse mismatches rather than
+// read them.
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(d *Diff) string {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return diffFormatter{}.Format(d)
+	}
+	return string(b)
+}
+
+// JSONFormatter renders a Diff as JSON, for piping CheckArgs mismatches into another tool instead of
+// reading them as text.
+var JSONFormatter Formatter = jsonFormatter{}
@@ -0,0 +1,65 @@
+package testaroli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLegacyFormatter(t *testing.T) {
+	msg := LegacyFormatter.Format(&Diff{Actual: 1, Expected: 2, Kind: DiffValueMismatch})
+	if msg != "actual value '1' differs from expected '2'" {
+		t.Errorf("unexpected message: %s", msg)
+	}
+	msg = LegacyFormatter.Format(&Diff{Actual: "int", Expected: "string", Kind: DiffTypeMismatch})
+	if msg != "actual type 'int' differs from expected 'string'" {
+		t.Errorf("unexpected message: %s", msg)
+	}
+}
+
+func TestDiffFormatter(t *testing.T) {
+	msg := DiffFormatter.Format(&Diff{Path: ".Balance", Actual: 234.56, Expected: 235.79, Kind: DiffValueMismatch})
+	if msg != ".Balance: -234.56 +235.79" {
+		t.Errorf("unexpected message: %s", msg)
+	}
+	msg = DiffFormatter.Format(&Diff{Actual: 234.56, Expected: 235.79, Kind: DiffValueMismatch})
+	if !strings.HasPrefix(msg, "value:") {
+		t.Errorf("expected empty path to render as 'value', got: %s", msg)
+	}
+}
+
+func TestDiffFormatterTruncates(t *testing.T) {
+	long := strings.Repeat("x", maxFormattedValueLen+10)
+	msg := DiffFormatter.Format(&Diff{Path: ".Name", Actual: long, Expected: "short", Kind: DiffValueMismatch})
+	if strings.Contains(msg, long) {
+		t.Error("long value should have been truncated")
+	}
+	if !strings.HasSuffix(msg, "+short") {
+		t.Errorf("unexpected message: %s", msg)
+	}
+}
+
+func TestColorFormatter(t *testing.T) {
+	msg := ColorFormatter.Format(&Diff{Path: ".Balance", Actual: 1, Expected: 2, Kind: DiffValueMismatch})
+	if !strings.Contains(msg, ansiRed) || !strings.Contains(msg, ansiGreen) || !strings.Contains(msg, ansiReset) {
+		t.Errorf("expected ANSI colour codes in message: %s", msg)
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	msg := JSONFormatter.Format(&Diff{Path: ".Balance", Actual: float64(1), Expected: float64(2), Kind: DiffValueMismatch})
+	if !strings.Contains(msg, `"Path":".Balance"`) {
+		t.Errorf("unexpected JSON message: %s", msg)
+	}
+}
+
+func TestWithFormatter(t *testing.T) {
+	ctx := context.Background()
+	if formatterOf(ctx) != DefaultFormatter {
+		t.Error("expected DefaultFormatter when none attached")
+	}
+	ctx = WithFormatter(ctx, LegacyFormatter)
+	if formatterOf(ctx) != LegacyFormatter {
+		t.Error("expected attached formatter to be returned")
+	}
+}
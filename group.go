@@ -0,0 +1,164 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+package testaroli
+
+import "context"
+
+/*
+OverrideGroup is a set of overrides that become active together, built with [Group]. A plain [Override]
+call occupies one slot in the chain on its own; a group lets several overrides share a single slot, so
+the function under test is free to call them in whatever order it likes. Use [InOrder] to additionally
+require that one group not start until another has fully drained, regardless of whether the two are
+adjacent in the chain.
+*/
+type OverrideGroup struct {
+	expectations []*Expect
+}
+
+/*
+Group registers every [Override] call made inside fn as a single group: all of them are patched at once
+instead of one at a time, so the code under test may call them in any order. Like a plain Override call,
+the group as a whole occupies one slot in the chain of overrides and only becomes active once every
+override registered before it has been fully consumed; the next slot in the chain, in turn, doesn't
+become active until every override in this group has been called its expected number of times.
+
+	g := Group(ctx, func(gctx context.Context) {
+	    Override(gctx, foo, Once, func(a int) { Expectation().CheckArgs(a) })(1)
+	    Override(gctx, bar, Once, func(a int) { Expectation().CheckArgs(a) })(2)
+	})
+
+Here foo and bar are both active at the same time, and the code under test may call either one first.
+*/
+func Group(ctx context.Context, fn func(ctx context.Context)) *OverrideGroup {
+	c := currentChain(ctx)
+
+	g := &OverrideGroup{}
+	c.groups = append(c.groups, g)
+
+	prev := c.collecting
+	c.collecting = g
+	fn(ctx)
+	c.collecting = prev
+
+	return g
+}
+
+// chainLink is one unit [InOrder] can sequence: a single [*Expect] (e.g. one obtained from a
+// one-override [Group]) or a whole [*OverrideGroup] whose members must all be consumed together.
+type chainLink interface {
+	members() []*Expect
+}
+
+func (e *Expect) members() []*Expect { return []*Expect{e} }
+
+func (g *OverrideGroup) members() []*Expect { return g.expectations }
+
+/*
+InOrder records that every member of links[i] must be fully consumed before any member of links[i+1] is
+allowed to fire, for every consecutive pair. Each link is either a single [*Expect] or a whole
+[*OverrideGroup]; it is implemented in terms of [Expect.NotBefore], so a violation panics with the same
+"invoked before prerequisite" message.
+
+Chain position already enforces this ordering for overrides/groups that happen to be adjacent in the
+order they were declared; InOrder is for links that aren't chain neighbours, or simply to state the
+constraint explicitly:
+
+	g1 := Group(ctx, func(gctx context.Context) { ... })
+	g2 := Group(ctx, func(gctx context.Context) { ... })
+	InOrder(g1, g2) // every override in g2 waits for all of g1 to drain
+
+A single (non-grouped) override's [*Expect] can be pulled out of a one-member [Group] the same way, and
+sequenced with InOrder like any other link:
+
+	g := Group(ctx, func(gctx context.Context) { Override(gctx, foo, Once, mock)(42) })
+	InOrder(g.expectations[0], g2)
+*/
+func InOrder(links ...chainLink) {
+	for i := 1; i < len(links); i++ {
+		for _, e := range links[i].members() {
+			e.NotBefore(links[i-1].members()...)
+		}
+	}
+}
+
+// allAlways reports whether every override in g has the [Always] count, meaning the group is
+// permanently active and never blocks the chain from advancing. An empty group (one still being
+// collected by [Group]) is not considered all-Always, so it can still be recognised as the active head.
+func allAlways(g *OverrideGroup) bool {
+	if len(g.expectations) == 0 {
+		return false
+	}
+	for _, e := range g.expectations {
+		if e.expCount != Always {
+			return false
+		}
+	}
+	return true
+}
+
+// allMaybe reports whether every override in g has the [Maybe] count, meaning the group is optional and
+// can be skipped over, whether or not any of its members have already been called, as soon as a call
+// arrives for a later override in the chain.
+func allMaybe(g *OverrideGroup) bool {
+	if len(g.expectations) == 0 {
+		return false
+	}
+	for _, e := range g.expectations {
+		if e.expCount != Maybe {
+			return false
+		}
+	}
+	return true
+}
+
+// headGroup returns the first group in the chain that isn't all-Always, i.e. the group whose overrides
+// are expected to be called next. It returns nil once every group has drained or is all-Always.
+func headGroup(c *expectChain) *OverrideGroup {
+	for _, g := range c.groups {
+		if !allAlways(g) {
+			return g
+		}
+	}
+	return nil
+}
+
+// lastGroup returns the most recently registered group in the chain, or nil if the chain is empty.
+func lastGroup(c *expectChain) *OverrideGroup {
+	if len(c.groups) == 0 {
+		return nil
+	}
+	return c.groups[len(c.groups)-1]
+}
+
+// groupHasUnlimited reports whether any override in g has the [Unlimited] count, or an unbounded
+// [Cardinality] (one built with [AtLeast] or [AnyTimes]) - both mean no further override can ever
+// become reachable.
+func groupHasUnlimited(g *OverrideGroup) bool {
+	for _, e := range g.expectations {
+		if (e.card == nil && e.expCount == Unlimited) || (e.card != nil && e.card.max < 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten returns every override registered on the chain, in registration order, regardless of group.
+func (c *expectChain) flatten() []*Expect {
+	var all []*Expect
+	for _, g := range c.groups {
+		all = append(all, g.expectations...)
+	}
+	return all
+}
@@ -0,0 +1,175 @@
+package testaroli
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGroupCallsInAnyOrder(t *testing.T) {
+	ctx := TestingContext(t)
+	Group(ctx, func(gctx context.Context) {
+		Override(gctx, bar, Once, func(i int) error {
+			Expectation().CheckArgs(i)
+			return nil
+		})(3)
+		Override(gctx, baz, Once, func(i int) error {
+			Expectation().CheckArgs(i)
+			return nil
+		})(2)
+	})
+
+	// called in the reverse of registration order - both are active at once, so this is fine
+	testError(t, nil, baz(2))
+	testError(t, nil, bar(3))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestGroupOccupiesOneChainSlot(t *testing.T) {
+	ctx := TestingContext(t)
+	Group(ctx, func(gctx context.Context) {
+		Override(gctx, bar, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(1)
+		Override(gctx, baz, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(2)
+	})
+	Override(ctx, qux, Once, func(err error) error {
+		Expectation()
+		return nil
+	})(nil)
+
+	// qux's override isn't active until every member of the group above has fired
+	bar(1)
+	baz(2)
+	testError(t, nil, qux(nil))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestGroupBlocksNextSlotUntilDrained(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("unexpected panic: %v", r)
+		}
+		ExpectationsWereMet()
+	}()
+
+	ctx := TestingContext(t)
+	Group(ctx, func(gctx context.Context) {
+		Override(gctx, bar, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(1)
+		Override(gctx, baz, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(2)
+	})
+	Override(ctx, qux, Once, func(err error) error {
+		Expectation()
+		return nil
+	})(nil)
+
+	// only bar() has been called, baz() is still outstanding, so qux isn't active yet and the
+	// original qux runs - it returns its argument, where the mock would have returned nil
+	bar(1)
+	dummy := errors.New("dummy")
+	testError(t, dummy, qux(dummy))
+}
+
+func TestInOrderAcrossGroups(t *testing.T) {
+	ctx := TestingContext(t)
+
+	g1 := Group(ctx, func(gctx context.Context) {
+		Override(gctx, bar, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(1)
+	})
+	g2 := Group(ctx, func(gctx context.Context) {
+		Override(gctx, baz, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(2)
+	})
+	InOrder(g1, g2)
+
+	testError(t, nil, bar(1))
+	testError(t, nil, baz(2))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestInOrderAcrossSingleExpects(t *testing.T) {
+	ctx := TestingContext(t)
+
+	g1 := Group(ctx, func(gctx context.Context) {
+		Override(gctx, bar, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(1)
+	})
+	g2 := Group(ctx, func(gctx context.Context) {
+		Override(gctx, baz, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(2)
+	})
+	// InOrder also accepts individual *Expect handles, pulled out of a one-member Group, alongside
+	// whole groups - here it's equivalent to InOrder(g1, g2)
+	InOrder(g1.expectations[0], g2.expectations[0])
+
+	testError(t, nil, bar(1))
+	testError(t, nil, baz(2))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestExpectAfterIsNotBefore(t *testing.T) {
+	ctx := TestingContext(t)
+
+	g1 := Group(ctx, func(gctx context.Context) {
+		Override(gctx, bar, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(1)
+	})
+	g2 := Group(ctx, func(gctx context.Context) {
+		Override(gctx, baz, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(2)
+	})
+	g2.expectations[0].After(g1.expectations[0])
+
+	testError(t, nil, bar(1))
+	testError(t, nil, baz(2))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestInOrderViolationPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("the code did not panic")
+		}
+		ExpectationsWereMet()
+	}()
+
+	ctx := TestingContext(t)
+
+	// both overrides share one group, so both are patched at once and only InOrder orders them
+	g := Group(ctx, func(gctx context.Context) {
+		Override(gctx, bar, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(1)
+		Override(gctx, baz, Once, func(i int) error {
+			Expectation()
+			return nil
+		})(2)
+	})
+	InOrder(g.expectations[0], g.expectations[1])
+
+	baz(2) // bar() hasn't been called yet, so this violates the InOrder constraint
+}
@@ -0,0 +1,165 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && (amd64 || arm64)
+
+/*
+Hook support lets a test observe a function being called without replacing what it does, unlike
+[Override], which overwrites the whole prologue with a JMP to the mock so the original body never
+runs at all. Hook still patches the prologue with a JMP - there's no cheaper way to intercept a plain
+function call in a running binary - but the bytes it overwrites are copied into a small trampoline
+first, and the mock jumps through that trampoline to run the original body before returning, so org's
+real result is what the caller actually gets.
+
+Typical use - asserting that bar() is called as part of foo(), and that foo() still sees bar's real
+result, unlike with Override where the mock entirely decides what's returned:
+
+	func TestFooCallsBar(t *testing.T) {
+	    var entered, left bool
+	    Hook(TestingContext(t), bar, Once, func() { entered = true }, func() { left = true })
+
+	    foo()
+
+	    if !entered || !left {
+	        t.Error("bar() was not called")
+	    }
+	}
+
+pre runs immediately before org, post immediately after it returns; either may be nil but not both.
+count follows the same rules as [Override]'s: a positive number of expected calls, or [Unlimited],
+[Always] or [Maybe] - see [Override] for what each of them means. Like [Override], Hook takes a
+context created with [TestingContext] (or derived from one), and the hook is automatically removed via
+[testing.T.Cleanup], which also reports an unmet count the same way [ExpectationsWereMet] would for an
+unconsumed override.
+
+Currently supported OS/arch combinations:
+  - Linux / x86_64
+  - Linux / ARM64
+*/
+package testaroli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// funcval is the runtime representation of a Go function value: a pointer to a struct whose first
+// word is the function's entry point. Hook uses it to point a variable of generic type T at a
+// trampoline it built itself, the same way the Go runtime points it at compiled code.
+type funcval struct {
+	fn uintptr
+}
+
+/*
+Hook arms org so that pre runs just before its original body and post (if not nil) runs right after it
+returns - unlike [Override], org's own behaviour is preserved and its result is whatever it would
+normally be.
+*/
+func Hook[T any](ctx context.Context, org T, count int, pre, post func()) T {
+	if reflect.ValueOf(org).Kind() != reflect.Func {
+		panic("Hook() can be called only for function/method")
+	}
+	if count < minOccurenceCount || count == 0 {
+		panic("Invalid count: must be a positive number or Unlimited/Always/Maybe")
+	}
+	if pre == nil && post == nil {
+		panic("Hook() needs at least one of pre or post")
+	}
+
+	t := Testing(ctx) // just to make sure the context is correct
+
+	orgPointer := reflect.ValueOf(org).UnsafePointer()
+	orgName := runtime.FuncForPC(uintptr(orgPointer)).Name()
+
+	// detour is set below, once the trampoline it points at exists; the mock closure only resolves
+	// it when actually called, which never happens before Hook returns.
+	var detour T
+	actCount := 0
+
+	typ := reflect.TypeOf(org)
+	v := reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		if pre != nil {
+			pre()
+		}
+		actCount++
+		results := reflect.ValueOf(detour).Call(args)
+		if post != nil {
+			post()
+		}
+		return results
+	})
+
+	var mock T
+	fn := reflect.ValueOf(&mock).Elem()
+	fn.Set(v)
+	mockPointer := reflect.ValueOf(mock).UnsafePointer()
+
+	var orgPrologue []byte
+	var trampoline unsafe.Pointer
+	withPatchLock(func() {
+		orgPrologue = override(orgPointer, mockPointer) // call arch-specific function
+		trampoline = buildTrampoline(orgPointer, orgPrologue)
+	})
+
+	setFuncCode(&detour, trampoline)
+
+	t.Cleanup(func() {
+		withPatchLock(func() {
+			reset(orgPointer, orgPrologue)
+		})
+		if count == Unlimited || count == Always || count == Maybe {
+			return
+		}
+		if actCount == 0 {
+			t.Errorf("function %s was not called", orgName)
+		} else if actCount != count {
+			t.Errorf("function %s was called %d time(s) instead of %d", orgName, actCount, count)
+		}
+	})
+
+	return mock
+}
+
+// buildTrampoline builds a small piece of executable memory that contains a copy of orgPrologue - the
+// bytes override() just replaced with a JMP to the mock - followed by a JMP back into org, landing
+// right after the bytes it copied. Calling through it runs exactly the body org would have run had it
+// never been patched.
+func buildTrampoline(orgPointer unsafe.Pointer, orgPrologue []byte) unsafe.Pointer {
+	n := len(orgPrologue)
+	mem, err := unix.Mmap(-1, 0, n*2, unix.PROT_READ|unix.PROT_WRITE|unix.PROT_EXEC,
+		unix.MAP_PRIVATE|unix.MAP_ANONYMOUS)
+	if err != nil {
+		panic(fmt.Sprintf("cannot allocate executable memory for trampoline: %v", err))
+	}
+
+	copy(mem, orgPrologue)
+	resumeAt := unsafe.Pointer(uintptr(orgPointer) + uintptr(n))
+	jumpBack := unsafe.Pointer(&mem[n])
+	copy(mem[n:], buildJump(jumpBack, resumeAt)) // call arch-specific function
+
+	flushTrampoline(unsafe.Pointer(&mem[0]), len(mem)) // call arch-specific function
+
+	return unsafe.Pointer(&mem[0])
+}
+
+// setFuncCode points fn, a variable of generic function type T, at code by rewriting fn's underlying
+// funcval pointer - the same representation the Go runtime itself uses for a function value - instead
+// of calling code directly, so detour keeps its proper, type-checked T signature all the way through.
+func setFuncCode[T any](fn *T, code unsafe.Pointer) {
+	fv := &funcval{fn: uintptr(code)}
+	*(*unsafe.Pointer)(unsafe.Pointer(fn)) = unsafe.Pointer(fv)
+}
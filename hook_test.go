@@ -0,0 +1,86 @@
+//go:build linux && (amd64 || arm64)
+
+package testaroli
+
+import "testing"
+
+func TestHookPreAndPost(t *testing.T) {
+	var entered, left bool
+
+	Hook(TestingContext(t), qux, Once,
+		func() { entered = true },
+		func() { left = true })
+
+	err := bar(3) // bar(3) -> qux(errors.New("even")), which Hook must not replace
+
+	if !entered || !left {
+		t.Errorf("pre/post ran entered=%v left=%v, want both true", entered, left)
+	}
+	if err == nil || err.Error() != "even" {
+		t.Errorf("qux's real result was not preserved, got %v", err)
+	}
+}
+
+func TestHookPreOnly(t *testing.T) {
+	var calls int
+
+	Hook(TestingContext(t), baz, Once, func() { calls++ }, nil)
+
+	// foo(101) -> baz(100), then bar(1) -> qux(errors.New("even"))
+	if err := foo(101); err == nil || err.Error() != "even" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("pre ran %d times, want 1", calls)
+	}
+}
+
+func TestHookUnlimited(t *testing.T) {
+	var calls int
+
+	Hook(TestingContext(t), qux, Unlimited, func() { calls++ }, nil)
+
+	bar(3)
+	bar(4)
+	if calls != 2 {
+		t.Errorf("pre ran %d times, want 2", calls)
+	}
+}
+
+func TestHookMaybeNotCalledIsNotAnError(t *testing.T) {
+	ok := t.Run("subtest", func(st *testing.T) {
+		Hook(TestingContext(st), qux, Maybe, func() {}, nil)
+		// deliberately never call qux - Maybe must not report an error on cleanup
+	})
+	if !ok {
+		t.Errorf("Maybe hook that was never called should not fail the test")
+	}
+}
+
+func TestHookOnceNotCalledIsAnError(t *testing.T) {
+	ok := t.Run("subtest", func(st *testing.T) {
+		Hook(TestingContext(st), qux, Once, func() {}, nil)
+		// deliberately never call qux - Once must report an error on cleanup
+	})
+	if ok {
+		t.Errorf("Once hook that was never called should fail the test")
+	}
+}
+
+func TestHookRejectsInvalidCount(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic for an invalid count")
+		}
+	}()
+	Hook(TestingContext(t), qux, 0, func() {}, nil)
+}
+
+func TestHookRejectsNoCallbacks(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic when neither pre nor post is given")
+		}
+	}()
+	Hook(TestingContext(t), qux, Once, nil, nil)
+}
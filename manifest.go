@@ -0,0 +1,304 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+/*
+Manifest support lets a whole override chain be described declaratively instead of as Go code, mirroring
+how mockery's .mockery.yaml describes a mock surface outside of Go. A manifest is a restricted YAML
+subset - a top-level list of mappings, each describing one [Override] call:
+
+  - target: mypkg.bar
+    count: Once
+    args: [42, "qwerty"]
+    return: [nil]
+  - target: "(*os.File).Read"
+    count: 3
+    return: [3, nil]
+    os: linux || darwin
+
+`target` must have been registered with [Register] beforehand. `count` is Once/Unlimited/Always/Maybe or
+a positive integer. `args` and `return` are Go literal scalars (ints, quoted strings, true/false, nil).
+`os` is optional and uses the same boolean syntax as a `//go:build` line; the entry is skipped unless it
+evaluates true for the current GOOS/GOARCH.
+*/
+package testaroli
+
+import (
+	"context"
+	"fmt"
+	"go/build/constraint"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+/*
+ManifestEntry is a single parsed entry of a manifest loaded with [LoadManifest].
+*/
+type ManifestEntry struct {
+	Target string
+	Count  int
+	Args   []any
+	Return []any
+	OS     string // build-constraint expression, e.g. "linux || darwin"; empty means always applies
+}
+
+/*
+LoadManifest reads the manifest file at path and applies every entry whose `os` constraint (if any)
+matches the current GOOS/GOARCH, wiring each one through [Override] exactly as if it had been written by
+hand. Every `target` referenced by the manifest must already have been registered with [Register].
+
+Entries are applied in the order they appear in the file, so, same as with hand-written overrides, that
+order must match the order the functions are expected to be called in.
+*/
+func LoadManifest(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading manifest %s: %w", path, err)
+	}
+
+	entries, err := parseManifest(data)
+	if err != nil {
+		return fmt.Errorf("parsing manifest %s: %w", path, err)
+	}
+
+	for _, e := range entries {
+		if e.OS != "" {
+			applies, err := osConstraintMet(e.OS)
+			if err != nil {
+				return fmt.Errorf("entry %s: %w", e.Target, err)
+			}
+			if !applies {
+				continue
+			}
+		}
+		if err := applyEntry(ctx, e); err != nil {
+			return fmt.Errorf("entry %s: %w", e.Target, err)
+		}
+	}
+	return nil
+}
+
+// osConstraintMet evaluates expr (the part of a //go:build line after the directive) against the
+// current GOOS and GOARCH.
+func osConstraintMet(expr string) (bool, error) {
+	x, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		return false, fmt.Errorf("invalid os constraint %q: %w", expr, err)
+	}
+	return x.Eval(func(tag string) bool {
+		return tag == runtime.GOOS || tag == runtime.GOARCH
+	}), nil
+}
+
+// applyEntry looks up e.Target in the registry and wires it through [Override], using reflection since
+// the target's concrete function type isn't known until runtime.
+func applyEntry(ctx context.Context, e ManifestEntry) error {
+	fn, err := lookup(e.Target)
+	if err != nil {
+		return err
+	}
+	orgType := reflect.TypeOf(fn)
+
+	mock := reflect.MakeFunc(orgType, func(args []reflect.Value) []reflect.Value {
+		Expectation().CheckArgs(valuesToAny(args)...)
+		return valuesToResults(orgType, e.Return)
+	})
+
+	expectedArgsFunc := Override[any](ctx, fn, e.Count, mock.Interface())
+	if len(e.Args) > 0 {
+		argValues, err := anyToArgs(orgType, e.Args)
+		if err != nil {
+			return err
+		}
+		reflect.ValueOf(expectedArgsFunc).Call(argValues)
+	}
+	return nil
+}
+
+// valuesToAny converts the actual call arguments received by a mock into plain values, for CheckArgs.
+func valuesToAny(args []reflect.Value) []any {
+	out := make([]any, len(args))
+	for i, a := range args {
+		out[i] = a.Interface()
+	}
+	return out
+}
+
+// anyToArgs converts manifest-supplied literal values into reflect.Values matching typ's in-types, for
+// calling the function [Override] returns in order to set the expected arguments.
+func anyToArgs(typ reflect.Type, values []any) ([]reflect.Value, error) {
+	if len(values) != typ.NumIn() {
+		return nil, fmt.Errorf("expected %d args, manifest gives %d", typ.NumIn(), len(values))
+	}
+	args := make([]reflect.Value, len(values))
+	for i, val := range values {
+		inType := typ.In(i)
+		if val == nil {
+			args[i] = reflect.Zero(inType)
+			continue
+		}
+		v := reflect.ValueOf(val)
+		if !v.Type().AssignableTo(inType) {
+			return nil, fmt.Errorf("arg %d: value of type %s is not assignable to %s", i, v.Type(), inType)
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+// parseManifest parses the restricted YAML subset documented at the top of this file: a top-level list of
+// mappings, each "- key: value" line starting a new entry and each indented "key: value" line adding to
+// the current one. It deliberately doesn't pull in a full YAML parser - the format it accepts is a small,
+// fixed shape, not general YAML.
+func parseManifest(data []byte) ([]ManifestEntry, error) {
+	var entries []ManifestEntry
+	for n, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimRight(raw, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") {
+			entries = append(entries, ManifestEntry{})
+			trimmed = strings.TrimSpace(trimmed[2:])
+		} else if len(entries) == 0 {
+			return nil, fmt.Errorf("line %d: expected a list entry starting with '- '", n+1)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected 'key: value'", n+1)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		e := &entries[len(entries)-1]
+		var err error
+		switch key {
+		case "target":
+			e.Target, err = parseString(value)
+		case "count":
+			e.Count, err = parseCount(value)
+		case "args":
+			e.Args, err = parseScalarList(value)
+		case "return":
+			e.Return, err = parseScalarList(value)
+		case "os":
+			e.OS, err = parseString(value)
+		default:
+			err = fmt.Errorf("unknown key %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+	}
+	return entries, nil
+}
+
+// parseCount parses a manifest count value: one of the named constants, or a plain integer.
+func parseCount(s string) (int, error) {
+	switch s {
+	case "Once":
+		return Once, nil
+	case "Unlimited":
+		return Unlimited, nil
+	case "Always":
+		return Always, nil
+	case "Maybe":
+		return Maybe, nil
+	default:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid count %q", s)
+		}
+		return n, nil
+	}
+}
+
+// parseScalarList parses a "[a, b, c]" inline list of scalars. An empty value yields a nil slice.
+func parseScalarList(s string) ([]any, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("expected '[...]', got %q", s)
+	}
+	inner := strings.TrimSpace(s[1 : len(s)-1])
+	if inner == "" {
+		return nil, nil
+	}
+	var out []any
+	for _, item := range splitTopLevel(inner) {
+		v, err := parseScalar(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// splitTopLevel splits s on commas that are not inside a quoted string.
+func splitTopLevel(s string) []string {
+	var parts []string
+	var quoted bool
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, s[start:])
+	return parts
+}
+
+// parseScalar parses a single manifest literal: nil, true/false, a quoted string, or an integer.
+func parseScalar(s string) (any, error) {
+	switch s {
+	case "nil":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		return parseString(s)
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	return nil, fmt.Errorf("unsupported literal %q - use nil, true/false, a quoted string or an integer", s)
+}
+
+// parseString unquotes a manifest string value; an unquoted bare word is accepted as-is.
+func parseString(s string) (string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return s, nil
+	}
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid quoted string %q: %w", s, err)
+	}
+	return unquoted, nil
+}
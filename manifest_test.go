@@ -0,0 +1,115 @@
+package testaroli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	data := []byte(`
+# a comment line, and a blank line above
+- target: bar
+  count: Once
+  args: [42, "qwerty"]
+  return: [nil]
+- target: "(*os.File).Read"
+  count: 3
+  return: [3, nil]
+  os: linux || darwin
+`)
+	entries, err := parseManifest(data)
+	if err != nil {
+		t.Fatalf("parseManifest: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	e := entries[0]
+	if e.Target != "bar" || e.Count != Once {
+		t.Errorf("entry 0: got target=%q count=%d", e.Target, e.Count)
+	}
+	if len(e.Args) != 2 || e.Args[0] != 42 || e.Args[1] != "qwerty" {
+		t.Errorf("entry 0: unexpected args %#v", e.Args)
+	}
+	if len(e.Return) != 1 || e.Return[0] != nil {
+		t.Errorf("entry 0: unexpected return %#v", e.Return)
+	}
+
+	e = entries[1]
+	if e.Target != "(*os.File).Read" || e.Count != 3 || e.OS != "linux || darwin" {
+		t.Errorf("entry 1: got %#v", e)
+	}
+	if len(e.Return) != 2 || e.Return[0] != 3 || e.Return[1] != nil {
+		t.Errorf("entry 1: unexpected return %#v", e.Return)
+	}
+}
+
+func TestParseManifestRejectsMalformedLine(t *testing.T) {
+	if _, err := parseManifest([]byte("target: bar\n")); err == nil {
+		t.Errorf("expected error for entry missing '- ' prefix")
+	}
+	if _, err := parseManifest([]byte("- target bar\n")); err == nil {
+		t.Errorf("expected error for line missing ':'")
+	}
+}
+
+func TestLoadManifestAppliesEntries(t *testing.T) {
+	Register("bar", bar)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "overrides.yaml")
+	manifest := `
+- target: bar
+  count: Once
+  args: [2]
+  return: [nil]
+`
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+
+	if err := LoadManifest(TestingContext(t), path); err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+
+	testError(t, nil, foo(1)) // foo(1) -> bar(2), overridden above
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestLoadManifestSkipsUnmatchedOS(t *testing.T) {
+	Register("bar", bar)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "overrides.yaml")
+	manifest := `
+- target: bar
+  count: Once
+  os: this_os_does_not_exist
+`
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+
+	if err := LoadManifest(TestingContext(t), path); err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+
+	// entry was skipped, so bar() was never overridden and there's nothing to clean up
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestLoadManifestUnregisteredTargetErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "overrides.yaml")
+	manifest := "- target: neverRegistered\n  count: Once\n"
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+
+	err := LoadManifest(TestingContext(t), path)
+	if err == nil {
+		t.Fatalf("expected error for unregistered target")
+	}
+}
@@ -0,0 +1,328 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testaroli
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+/*
+Matcher allows to check an actual argument value against something other than strict equality. Pass a
+Matcher instead of a literal value to [Expect.Expect], and [Expect.CheckArgs] will call Matches on the
+actual argument instead of comparing it with [reflect.DeepEqual]-like logic. String is used to describe
+the matcher in failure messages, mirroring the style used by other Go mock frameworks.
+
+Note that because [Override] returns a function typed exactly as the overridden one, a Matcher can only
+be passed through [Expect.Expect], not through the generic function returned by Override.
+*/
+type Matcher interface {
+	Matches(x any) bool
+	String() string
+}
+
+type anyMatcher struct{}
+
+func (anyMatcher) Matches(any) bool { return true }
+func (anyMatcher) String() string   { return "Any()" }
+
+// Any returns a [Matcher] that matches any value, including nil.
+func Any() Matcher { return anyMatcher{} }
+
+type eqMatcher struct {
+	val any
+}
+
+func (m eqMatcher) Matches(x any) bool {
+	res, _ := equal(reflect.ValueOf(x), reflect.ValueOf(m.val))
+	return res
+}
+func (m eqMatcher) String() string { return fmt.Sprintf("Eq(%v)", m.val) }
+
+// Eq returns a [Matcher] that matches a value equal to v, using the same comparison rules as CheckArgs
+// uses for plain (non-matcher) expected values.
+func Eq(v any) Matcher { return eqMatcher{val: v} }
+
+type nilMatcher struct{ want bool }
+
+func (m nilMatcher) Matches(x any) bool {
+	if x == nil {
+		return m.want
+	}
+	v := reflect.ValueOf(x)
+	return isNillable(v) && v.IsNil() == m.want
+}
+func (m nilMatcher) String() string {
+	if m.want {
+		return "Nil()"
+	}
+	return "NotNil()"
+}
+
+// Nil returns a [Matcher] that matches a nil value.
+func Nil() Matcher { return nilMatcher{want: true} }
+
+// NotNil returns a [Matcher] that matches any non-nil value.
+func NotNil() Matcher { return nilMatcher{want: false} }
+
+type lenMatcher struct{ n int }
+
+func (m lenMatcher) Matches(x any) bool {
+	v := reflect.ValueOf(x)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String, reflect.Chan:
+		return v.Len() == m.n
+	default:
+		return false
+	}
+}
+func (m lenMatcher) String() string { return fmt.Sprintf("Len(%d)", m.n) }
+
+// Len returns a [Matcher] that matches a slice, array, map, string or channel of length n.
+func Len(n int) Matcher { return lenMatcher{n: n} }
+
+type assignableMatcher struct{ typ reflect.Type }
+
+func (m assignableMatcher) Matches(x any) bool {
+	if x == nil {
+		return false
+	}
+	return reflect.TypeOf(x).AssignableTo(m.typ)
+}
+func (m assignableMatcher) String() string { return fmt.Sprintf("AssignableToTypeOf(%s)", m.typ) }
+
+// AssignableToTypeOf returns a [Matcher] that matches any value assignable to the type of v.
+func AssignableToTypeOf(v any) Matcher { return assignableMatcher{typ: reflect.TypeOf(v)} }
+
+// AssignableTo returns a [Matcher] that matches any value assignable to t, for the (common for
+// interface types like context.Context) case where there's no sample value to pass to
+// [AssignableToTypeOf], only the [reflect.Type] itself, e.g. AssignableTo(reflect.TypeOf((*error)(nil)).Elem()).
+func AssignableTo(t reflect.Type) Matcher { return assignableMatcher{typ: t} }
+
+type condMatcher[T any] struct {
+	fn func(T) bool
+}
+
+func (m condMatcher[T]) Matches(x any) bool {
+	v, ok := x.(T)
+	if !ok {
+		return false
+	}
+	return m.fn(v)
+}
+func (m condMatcher[T]) String() string { return "Cond(...)" }
+
+// Cond returns a [Matcher] that matches a value of type T for which fn returns true.
+func Cond[T any](fn func(T) bool) Matcher { return condMatcher[T]{fn: fn} }
+
+type rangeMatcher[T cmp.Ordered] struct {
+	min, max T
+}
+
+func (m rangeMatcher[T]) Matches(x any) bool {
+	v, ok := x.(T)
+	if !ok {
+		return false
+	}
+	return v >= m.min && v <= m.max
+}
+func (m rangeMatcher[T]) String() string { return fmt.Sprintf("InRange(%v, %v)", m.min, m.max) }
+
+// InRange returns a [Matcher] that matches an ordered value v such that min <= v <= max.
+func InRange[T cmp.Ordered](min, max T) Matcher { return rangeMatcher[T]{min: min, max: max} }
+
+type inAnyOrderMatcher struct {
+	want []any
+}
+
+func (m inAnyOrderMatcher) Matches(x any) bool {
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false
+	}
+	if v.Len() != len(m.want) {
+		return false
+	}
+	used := make([]bool, len(m.want))
+	for i := 0; i < v.Len(); i++ {
+		found := false
+		for j, w := range m.want {
+			if used[j] {
+				continue
+			}
+			if matches(w, v.Index(i).Interface()) {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+func (m inAnyOrderMatcher) String() string { return fmt.Sprintf("InAnyOrder(%v)", m.want) }
+
+// InAnyOrder returns a [Matcher] that matches a slice or array containing exactly the given elements
+// (each of which may itself be a Matcher), regardless of their order.
+func InAnyOrder(want ...any) Matcher { return inAnyOrderMatcher{want: want} }
+
+type typeMatcher[T any] struct{}
+
+func (typeMatcher[T]) Matches(x any) bool {
+	_, ok := x.(T)
+	return ok
+}
+func (typeMatcher[T]) String() string { return fmt.Sprintf("AnyOfType[%T]()", *new(T)) }
+
+// AnyOfType returns a [Matcher] that matches any value of type T, regardless of its value.
+func AnyOfType[T any]() Matcher { return typeMatcher[T]{} }
+
+type regexpMatcher struct {
+	re *regexp.Regexp
+}
+
+func (m regexpMatcher) Matches(x any) bool {
+	s, ok := x.(string)
+	if !ok {
+		if v := reflect.ValueOf(x); v.Kind() == reflect.String {
+			s, ok = v.String(), true
+		}
+	}
+	return ok && m.re.MatchString(s)
+}
+func (m regexpMatcher) String() string { return fmt.Sprintf("MatchesRegexp(%q)", m.re.String()) }
+
+// MatchesRegexp returns a [Matcher] that matches a string value against the regular expression pat. It
+// panics if pat doesn't compile, the same way [regexp.MustCompile] does.
+func MatchesRegexp(pat string) Matcher { return regexpMatcher{re: regexp.MustCompile(pat)} }
+
+type fieldsMatcher struct {
+	want map[string]any
+}
+
+func (m fieldsMatcher) Matches(x any) bool {
+	v := reflect.ValueOf(x)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return false
+	}
+	for name, want := range m.want {
+		f := v.FieldByName(name)
+		if !f.IsValid() || !matches(want, f.Interface()) {
+			return false
+		}
+	}
+	return true
+}
+func (m fieldsMatcher) String() string { return fmt.Sprintf("HasFields(%v)", m.want) }
+
+// HasFields returns a [Matcher] that matches a struct (or pointer to struct) whose named fields equal
+// the given values - each of which may itself be a [Matcher] - ignoring every field not mentioned.
+func HasFields(want map[string]any) Matcher { return fieldsMatcher{want: want} }
+
+type containsMatcher struct {
+	want []any
+}
+
+func (m containsMatcher) Matches(x any) bool {
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false
+	}
+	for _, w := range m.want {
+		found := false
+		for i := 0; i < v.Len(); i++ {
+			if matches(w, v.Index(i).Interface()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+func (m containsMatcher) String() string { return fmt.Sprintf("SliceContaining(%v)", m.want) }
+
+// SliceContaining returns a [Matcher] that matches a slice or array containing at least one element
+// matching each of want - each of which may itself be a [Matcher] - regardless of order, duplicates or
+// any other elements present.
+func SliceContaining(want ...any) Matcher { return containsMatcher{want: want} }
+
+type pointerMatcher struct {
+	inner Matcher
+}
+
+func (m pointerMatcher) Matches(x any) bool {
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return false
+	}
+	return m.inner.Matches(v.Elem().Interface())
+}
+func (m pointerMatcher) String() string { return fmt.Sprintf("PointerTo(%s)", m.inner.String()) }
+
+// PointerTo returns a [Matcher] that matches a non-nil pointer whose pointed-to value matches inner.
+func PointerTo(inner Matcher) Matcher { return pointerMatcher{inner: inner} }
+
+type approxMatcher struct {
+	want, eps float64
+}
+
+func (m approxMatcher) Matches(x any) bool {
+	v := reflect.ValueOf(x)
+	if !v.CanFloat() {
+		return false
+	}
+	diff := v.Float() - m.want
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= m.eps
+}
+func (m approxMatcher) String() string { return fmt.Sprintf("Approx(%v, %v)", m.want, m.eps) }
+
+// Approx returns a [Matcher] that matches a float value within eps of want.
+func Approx(want, eps float64) Matcher { return approxMatcher{want: want, eps: eps} }
+
+type restMatcher struct {
+	inner Matcher
+}
+
+func (m restMatcher) Matches(x any) bool { return m.inner.Matches(x) }
+func (m restMatcher) String() string     { return fmt.Sprintf("Rest(%s)", m.inner.String()) }
+
+// Rest returns a [Matcher] that, when passed as the last value to [Expect.Expect], matches every
+// remaining actual argument (zero or more) against inner individually instead of requiring an expected
+// value per argument - useful for a variadic function's trailing arguments. See also
+// [Expect.CheckVariadic] for applying a distinct matcher to each trailing argument.
+func Rest(inner Matcher) Matcher { return restMatcher{inner: inner} }
+
+// matches checks actual value x against expected, which may be a literal value or a [Matcher].
+func matches(expected, x any) bool {
+	if m, ok := expected.(Matcher); ok {
+		return m.Matches(x)
+	}
+	res, _ := equal(reflect.ValueOf(x), reflect.ValueOf(expected))
+	return res
+}
@@ -0,0 +1,172 @@
+package testaroli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcherAny(t *testing.T) {
+	if !Any().Matches(42) {
+		t.Error("Any() should match any value")
+	}
+	if !Any().Matches(nil) {
+		t.Error("Any() should match nil")
+	}
+}
+
+func TestMatcherEq(t *testing.T) {
+	if !Eq(42).Matches(42) {
+		t.Error("Eq(42) should match 42")
+	}
+	if Eq(42).Matches(43) {
+		t.Error("Eq(42) should not match 43")
+	}
+}
+
+func TestMatcherNil(t *testing.T) {
+	var err error
+	if !Nil().Matches(err) {
+		t.Error("Nil() should match nil error")
+	}
+	if NotNil().Matches(err) {
+		t.Error("NotNil() should not match nil error")
+	}
+}
+
+func TestMatcherLen(t *testing.T) {
+	if !Len(3).Matches([]int{1, 2, 3}) {
+		t.Error("Len(3) should match slice of length 3")
+	}
+	if Len(3).Matches([]int{1, 2}) {
+		t.Error("Len(3) should not match slice of length 2")
+	}
+}
+
+func TestMatcherAssignableToTypeOf(t *testing.T) {
+	if !AssignableToTypeOf(0).Matches(42) {
+		t.Error("AssignableToTypeOf(0) should match an int")
+	}
+	if AssignableToTypeOf(0).Matches("42") {
+		t.Error("AssignableToTypeOf(0) should not match a string")
+	}
+}
+
+func TestMatcherAssignableTo(t *testing.T) {
+	if !AssignableTo(reflect.TypeOf(0)).Matches(42) {
+		t.Error("AssignableTo(int type) should match an int")
+	}
+	if AssignableTo(reflect.TypeOf(0)).Matches("42") {
+		t.Error("AssignableTo(int type) should not match a string")
+	}
+}
+
+func TestMatcherCond(t *testing.T) {
+	positive := Cond(func(i int) bool { return i > 0 })
+	if !positive.Matches(1) {
+		t.Error("Cond should match 1")
+	}
+	if positive.Matches(-1) {
+		t.Error("Cond should not match -1")
+	}
+}
+
+func TestMatcherInRange(t *testing.T) {
+	if !InRange(1, 10).Matches(5) {
+		t.Error("InRange(1,10) should match 5")
+	}
+	if InRange(1, 10).Matches(11) {
+		t.Error("InRange(1,10) should not match 11")
+	}
+	if !InRange(1, 10).Matches(1) || !InRange(1, 10).Matches(10) {
+		t.Error("InRange(1,10) should match its bounds")
+	}
+}
+
+func TestMatcherInAnyOrder(t *testing.T) {
+	if !InAnyOrder(1, 2, 3).Matches([]int{3, 1, 2}) {
+		t.Error("InAnyOrder(1,2,3) should match [3,1,2]")
+	}
+	if InAnyOrder(1, 2, 3).Matches([]int{1, 2}) {
+		t.Error("InAnyOrder(1,2,3) should not match a shorter slice")
+	}
+}
+
+func TestMatcherAnyOfType(t *testing.T) {
+	if !AnyOfType[int]().Matches(42) {
+		t.Error("AnyOfType[int]() should match an int")
+	}
+	if AnyOfType[int]().Matches("42") {
+		t.Error("AnyOfType[int]() should not match a string")
+	}
+}
+
+func TestMatcherMatchesRegexp(t *testing.T) {
+	if !MatchesRegexp(`^foo\d+$`).Matches("foo42") {
+		t.Error("MatchesRegexp should match foo42")
+	}
+	if MatchesRegexp(`^foo\d+$`).Matches("bar42") {
+		t.Error("MatchesRegexp should not match bar42")
+	}
+}
+
+func TestMatcherHasFields(t *testing.T) {
+	type point struct{ X, Y int }
+
+	if !HasFields(map[string]any{"X": 1}).Matches(point{X: 1, Y: 2}) {
+		t.Error("HasFields should match on a subset of fields")
+	}
+	if HasFields(map[string]any{"X": 2}).Matches(point{X: 1, Y: 2}) {
+		t.Error("HasFields should not match a differing field")
+	}
+	if !HasFields(map[string]any{"X": 1}).Matches(&point{X: 1, Y: 2}) {
+		t.Error("HasFields should match through a pointer")
+	}
+}
+
+func TestMatcherSliceContaining(t *testing.T) {
+	if !SliceContaining(2, 3).Matches([]int{1, 2, 3, 4}) {
+		t.Error("SliceContaining(2,3) should match a slice containing both")
+	}
+	if SliceContaining(5).Matches([]int{1, 2, 3, 4}) {
+		t.Error("SliceContaining(5) should not match a slice without 5")
+	}
+}
+
+func TestMatcherPointerTo(t *testing.T) {
+	i := 42
+	if !PointerTo(Eq(42)).Matches(&i) {
+		t.Error("PointerTo(Eq(42)) should match a pointer to 42")
+	}
+	if PointerTo(Eq(42)).Matches((*int)(nil)) {
+		t.Error("PointerTo(Eq(42)) should not match a nil pointer")
+	}
+}
+
+func TestMatcherApprox(t *testing.T) {
+	if !Approx(1.0, 0.01).Matches(1.005) {
+		t.Error("Approx(1.0, 0.01) should match 1.005")
+	}
+	if Approx(1.0, 0.01).Matches(1.1) {
+		t.Error("Approx(1.0, 0.01) should not match 1.1")
+	}
+}
+
+func TestMatcherRest(t *testing.T) {
+	if !Rest(Any()).Matches(1) || !Rest(Any()).Matches("x") {
+		t.Error("Rest(Any()) should match whatever its inner matcher matches")
+	}
+	if Rest(Eq(1)).Matches(2) {
+		t.Error("Rest(Eq(1)) should not match 2")
+	}
+}
+
+func TestCheckArgsWithMatcher(t *testing.T) {
+	Override(TestingContext(t), bar, Once, func(i int) error {
+		Expectation().Expect(Any()).CheckArgs(i)
+		return nil
+	})
+
+	err := bar(7)
+	testError(t, nil, err)
+	testError(t, nil, ExpectationsWereMet())
+}
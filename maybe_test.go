@@ -0,0 +1,61 @@
+package testaroli
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMaybeNotCalledIsNotAnError(t *testing.T) {
+	ctx := TestingContext(t)
+	Override(ctx, bar, Maybe, func(i int) error {
+		Expectation()
+		return nil
+	})(1)
+	Override(ctx, baz, Once, func(i int) error {
+		Expectation()
+		return nil
+	})(2)
+
+	// bar() is never called - baz() fires directly and the chain skips over the unused Maybe override
+	testError(t, nil, baz(2))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestMaybeCalledThenChainAdvances(t *testing.T) {
+	ctx := TestingContext(t)
+	Override(ctx, bar, Maybe, func(i int) error {
+		Expectation()
+		return nil
+	})(1)
+	Override(ctx, baz, Once, func(i int) error {
+		Expectation()
+		return nil
+	})(2)
+
+	testError(t, nil, bar(1))
+	testError(t, nil, bar(1))
+	testError(t, nil, baz(2))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestMaybeInGroupSkipped(t *testing.T) {
+	ctx := TestingContext(t)
+	Group(ctx, func(gctx context.Context) {
+		Override(gctx, bar, Maybe, func(i int) error {
+			Expectation()
+			return nil
+		})(1)
+		Override(gctx, baz, Maybe, func(i int) error {
+			Expectation()
+			return nil
+		})(2)
+	})
+	Override(ctx, qux, Once, func(err error) error {
+		Expectation()
+		return nil
+	})(nil)
+
+	// neither member of the optional group is called - qux() fires straight away
+	testError(t, nil, qux(nil))
+	testError(t, nil, ExpectationsWereMet())
+}
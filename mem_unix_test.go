@@ -31,6 +31,8 @@ func TestEndOfPage(t *testing.T) {
 func TestTwoPages(t *testing.T) {
 	pageSize := uintptr(os.Getpagesize())
 
+	// 0x10 is also the size of the arm64 island trampoline (see override_arm64.go), so this doubles
+	// as coverage for a veneer that happens to straddle a page boundary.
 	ptr, size := calcBoundaries(unsafe.Pointer(pageSize-0x4), 0x10)
 	if ptr != unsafe.Pointer(uintptr(0x00)) {
 		t.Error("incorrect page start")
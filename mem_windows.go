@@ -1,5 +1,5 @@
 // This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
-// Copyright (c) 2024 Ilya Caramishev. All rights reserved.
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -10,28 +10,100 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build windows && amd64
+
 package testaroli
 
 import (
+	"os"
 	"unsafe"
 
 	"golang.org/x/sys/windows"
 )
 
+// kernel32 exposes the handful of APIs golang.org/x/sys/windows doesn't wrap: SuspendThread and
+// FlushInstructionCache are not in its generated syscall table, so they are called directly.
+var (
+	kernel32                  = windows.NewLazySystemDLL("kernel32.dll")
+	procSuspendThread         = kernel32.NewProc("SuspendThread")
+	procFlushInstructionCache = kernel32.NewProc("FlushInstructionCache")
+)
+
 func replacePrologue(ptr unsafe.Pointer, buf []byte) {
 	err := makeMemRX(ptr, len(buf))
 	if err != nil {
 		panic(err)
 	}
+
+	// mirrors the Darwin backend's runtime.LockOSThread + whole-process freeze: without suspending
+	// every other thread, one of them could be mid-call at ptr while its prologue is overwritten
+	frozen, err := suspendOtherThreads()
+	if err != nil {
+		panic(err)
+	}
+	defer resumeThreads(frozen)
+
 	funcPrologue := unsafe.Slice((*uint8)(ptr), len(buf))
 	copy(funcPrologue, buf)
+
+	if ok, _, err := procFlushInstructionCache.Call(uintptr(windows.CurrentProcess()), uintptr(ptr), uintptr(len(buf))); ok == 0 {
+		panic(err)
+	}
 }
 
 func makeMemRX(ptr unsafe.Pointer, size int) error {
+	start, sz := calcBoundaries(ptr, size)
+
 	var oldPerms uint32
-	return windows.VirtualProtect(
-		uintptr(ptr),
-		uintptr(size),
-		windows.PAGE_EXECUTE_READWRITE,
-		&oldPerms)
+	return windows.VirtualProtect(uintptr(start), sz, windows.PAGE_EXECUTE_READWRITE, &oldPerms)
+}
+
+func calcBoundaries(ptr unsafe.Pointer, size int) (unsafe.Pointer, uintptr) {
+	pageSize := uintptr(os.Getpagesize())
+	areaStart := unsafe.Pointer(uintptr(ptr) &^ (pageSize - 1))
+	areaSize := (uintptr(ptr) + uintptr(size)) - uintptr(areaStart)
+
+	return areaStart, areaSize
+}
+
+// suspendOtherThreads pauses every thread of the current process except the calling one, returning
+// their handles so resumeThreads can wake them back up once the patch is applied.
+func suspendOtherThreads() ([]windows.Handle, error) {
+	self := windows.GetCurrentThreadId()
+	pid := windows.GetCurrentProcessId()
+
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPTHREAD, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer windows.CloseHandle(snap)
+
+	var entry windows.ThreadEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	var frozen []windows.Handle
+	for err = windows.Thread32First(snap, &entry); err == nil; err = windows.Thread32Next(snap, &entry) {
+		if entry.OwnerProcessID != pid || entry.ThreadID == self {
+			continue
+		}
+		h, err := windows.OpenThread(windows.THREAD_SUSPEND_RESUME, false, entry.ThreadID)
+		if err != nil {
+			continue // thread may have exited between the snapshot and OpenThread
+		}
+		if r1, _, _ := procSuspendThread.Call(uintptr(h)); r1 == 0xFFFFFFFF {
+			windows.CloseHandle(h)
+			continue
+		}
+		frozen = append(frozen, h)
+	}
+
+	return frozen, nil
+}
+
+// resumeThreads resumes and closes every handle suspendOtherThreads froze.
+func resumeThreads(frozen []windows.Handle) {
+	for _, h := range frozen {
+		windows.ResumeThread(h)
+		windows.CloseHandle(h)
+	}
 }
@@ -94,11 +94,14 @@ first argument of the mock function.
 package testaroli
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"runtime"
+	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -108,13 +111,82 @@ const (
 	Once              = 1
 	Unlimited         = -1
 	Always            = -2
-	minOccurenceCount = Always
+	Maybe             = -3
+	minOccurenceCount = Maybe
 	testingKey        = contextKey(1)
 )
 
-var expectations []*Expect
 var ErrExpectationsNotMet = errors.New("expectaions were not met")
 
+/*
+expectChain holds the overrides registered from a single goroutine (normally the goroutine running a
+test or subtest function), so that tests running under t.Parallel() don't share a single package-global
+chain and race on each other's bookkeeping. Chains are keyed off the goroutine ID in [chains].
+*/
+type expectChain struct {
+	t           *testing.T
+	groups      []*OverrideGroup // overrides, grouped by [Group]; a bare [Override] call gets a group of its own
+	collecting  *OverrideGroup   // set while inside a Group callback, so Override appends to it instead of starting a new group
+	cleanupDone bool
+	callLog     []RecordedCall // calls checked with [Expect.CheckArgs] so far, in the order they happened; see [Calls]
+}
+
+// chains maps a goroutine ID to the expectChain owned by that goroutine.
+var chains sync.Map // map[uint64]*expectChain
+
+// patchMu serializes the actual override()/reset() calls, which mutate shared executable memory, across
+// goroutines. It does not make concurrent overrides of the *same* function safe - that is still the
+// caller's responsibility - but it prevents unsynchronized writes from tearing each other's patches when
+// two tests running in parallel happen to patch at the same moment. Callers should go through
+// [withPatchLock] (see patch.go) rather than taking patchMu directly, so [SetPatchMode] is honoured.
+var patchMu sync.Mutex
+
+// goroutineID returns the ID of the calling goroutine, parsed out of the header line produced by
+// [runtime.Stack]. It keys [chains] and every other per-goroutine registry in the package (see
+// scope_linux.go, recorder.go, spy_mode.go), and is not meant to be a stable, public API.
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := bytes.Fields(buf[:n])
+	if len(fields) < 2 {
+		panic("cannot identify calling goroutine")
+	}
+	id, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil {
+		panic("cannot identify calling goroutine")
+	}
+	return id
+}
+
+// currentChain returns the expectChain owned by the calling goroutine, creating one (and registering a
+// t.Cleanup handler on the testing.T embedded in ctx) the first time it is called for that goroutine.
+func currentChain(ctx context.Context) *expectChain {
+	t := Testing(ctx) // just to make sure the context is correct
+	goid := goroutineID()
+	v, _ := chains.LoadOrStore(goid, &expectChain{t: t})
+	c := v.(*expectChain)
+	if !c.cleanupDone {
+		c.cleanupDone = true
+		t.Cleanup(func() {
+			if err := c.expectationsWereMet(); err != nil {
+				t.Errorf("%v", err)
+			}
+			chains.Delete(goid)
+		})
+	}
+	return c
+}
+
+// currentChainForDispatch returns the expectChain owned by the calling goroutine, for use from
+// [Expectation], which (unlike [Override]) has no context to resolve the owning *testing.T from.
+func currentChainForDispatch() *expectChain {
+	v, ok := chains.Load(goroutineID())
+	if !ok {
+		panic("unexpected function call - no overrides are active on this goroutine")
+	}
+	return v.(*expectChain)
+}
+
 /*
 Override overrides <org> with <mock>. The signatures of <org> and <mock> must match exactly,
 otherwise compilation error is reported.
@@ -123,6 +195,13 @@ a positive number, or [Unlimited]. After <org> function got called <count> times
 function is no longer overridden and next override in the chain becomes effective.
 [Unlimited] value for <count> means that there is no limit for number of <org> calls, and such override
 can only be the last one in the chain of overrides.
+[Maybe] value for <count> means that <org> may be called any number of times, including zero, while this
+override is active - unlike [Unlimited] it doesn't have to be the last override in the chain: as soon as a
+call arrives for the next override instead, this one is considered done and the chain advances past it.
+
+By default each Override call occupies its own slot in the chain, so overrides must become effective in
+the exact order they are expected to be called. Wrap several Override calls in [Group] when the code
+under test is free to call them in any order relative to each other.
 
 It is ok to call Override several times, however only the first override becomes immediately effecive,
 all subsequent overrides are placed in the chain and become effective only when previous override is
@@ -174,27 +253,56 @@ the variable from the context from within mock function, for example:
 	})
 
 You can override regular functions and methods, including standard ones, but not the interface methods.
+
+Overriding an instantiated generic function, e.g. Override(ctx, genericFunc[int], ...), patches the
+compiled code for that instantiation, same as for a regular function - with two wrinkles. First, the Go
+compiler shares one instantiation between every type argument that has the same GC shape (same size,
+same pointer layout), so overriding genericFunc[int] also overrides genericFunc[uint] and
+genericFunc[int64] on a 64-bit build, since all three share a shape; instantiations with a different
+shape, e.g. genericFunc[int32] or genericFunc[string], are unaffected. Second, a call the compiler
+resolves straight to the shape-generic implementation, passing the dictionary directly instead of going
+through the per-instantiation trampoline Override patches, bypasses the override even with
+-gcflags="all=-N -l" - unlike regular-function inlining, that flag doesn't prevent it. Fixing this
+would need recovering the dictionary pointer from the trampoline, rewriting every other trampoline
+sharing it, and patching the shape-generic implementation itself for the direct-call path; see
+[TestGenericDirectCall].
+
+Decision (chunk2-5): descoped, not implemented. Dictionary recovery and .gopclntab/moduledata.typelinks
+walking would add a second, largely-undocumented runtime-internals surface on top of the prologue
+patching this package already does, for a gap that only matters when the compiler happens to pick the
+direct-call path over the trampoline - something the caller doesn't control and Override can't detect.
+Recommended workaround: avoid overriding generic functions where the call site might take the direct
+path, or override a concrete non-generic wrapper around the generic call instead.
+
+org and mock may both be variadic, e.g. func(format string, args ...any) - Override doesn't need to
+treat this specially since mock is called with the same signature org has. [Expect.CheckArgs] matches
+a variadic tail whichever way you pass it, spread or collapsed into one slice; see [Expect.CheckVariadic]
+and [Rest] for checking the tail against one [Matcher] per argument.
 */
 func Override[T any](ctx context.Context, org T, count int, mock T) T {
 	if reflect.ValueOf(org).Kind() != reflect.Func || reflect.ValueOf(mock).Kind() != reflect.Func {
 		panic("Override() can be called only for function/method")
 	}
 
-	if len(expectations) > 0 && expectations[len(expectations)-1].expCount == Unlimited {
-		panic("Cannot override the function because previous override in chain has unlimited number of repetitions, therefore this override is unreachable")
+	c := currentChain(ctx)
+
+	// a bare Override starts a brand new slot in the chain, so it can't follow an Unlimited override;
+	// an Override made inside a [Group] callback joins the group already occupying its slot instead.
+	if c.collecting == nil {
+		if lg := lastGroup(c); lg != nil && groupHasUnlimited(lg) {
+			panic("Cannot override the function because previous override in chain has unlimited number of repetitions, therefore this override is unreachable")
+		}
 	}
 
 	if count < minOccurenceCount || count == 0 {
-		panic("Invalid count: must be a positive number or Never/Unlimited/Always")
+		panic("Invalid count: must be a positive number or Unlimited/Always/Maybe")
 	}
 
-	Testing(ctx) // just to make sure the context is correct
-
 	orgPointer := reflect.ValueOf(org).UnsafePointer()
 	mockPointer := reflect.ValueOf(mock).UnsafePointer()
 
 	// make sure override doesn't conflict for previous Always one
-	for _, e := range expectations {
+	for _, e := range c.flatten() {
 		if e.orgAddr == orgPointer {
 			if e.expCount == Always {
 				panic("Cannot override function that was previously overridden with 'Always' count")
@@ -204,15 +312,18 @@ func Override[T any](ctx context.Context, org T, count int, mock T) T {
 		}
 	}
 
-	expectedCall := Expect{
-		ctx:      ctx,
-		expCount: count,
-		mockAddr: mockPointer,
-		orgAddr:  orgPointer,
-		orgName:  runtime.FuncForPC(uintptr(orgPointer)).Name(),
+	typ := reflect.ValueOf(org).Type()
+
+	expectedCall := &Expect{
+		ctx:         ctx,
+		expCount:    count,
+		mockAddr:    mockPointer,
+		orgAddr:     orgPointer,
+		orgType:     typ,
+		orgVariadic: typ.IsVariadic(),
+		orgName:     runtime.FuncForPC(uintptr(orgPointer)).Name(),
 	}
 
-	typ := reflect.ValueOf(org).Type()
 	v := reflect.MakeFunc(
 		typ,
 		func(args []reflect.Value) []reflect.Value {
@@ -228,22 +339,97 @@ func Override[T any](ctx context.Context, org T, count int, mock T) T {
 	fn := reflect.ValueOf(&expectedArgsFunc).Elem()
 	fn.Set(v)
 
-	// all previous overrides are Always or this one it Always
-	if count == Always || len(expectations) == numLeadingAlways() {
-		expectedCall.orgPrologue = override(orgPointer, mockPointer) // call arch-specific function
+	var g *OverrideGroup
+	if c.collecting != nil {
+		g = c.collecting
+		g.expectations = append(g.expectations, expectedCall)
+	} else {
+		g = &OverrideGroup{expectations: []*Expect{expectedCall}}
+		c.groups = append(c.groups, g)
+	}
+
+	// this override is Always, or its group is the one currently at the head of the chain
+	if count == Always || g == headGroup(c) {
+		expectedCall.install()
 	}
-	expectations = append(expectations, &expectedCall)
 
 	return expectedArgsFunc
 }
 
-func numLeadingAlways() int {
-	for i, e := range expectations {
-		if e.expCount != Always {
-			return i
+/*
+OverrideCardinality is like [Override], but takes a [Cardinality] (built with [Times], [AtLeast],
+[AtMost], [Between] or [AnyTimes]) instead of an exact count, so expectations like "called between 2
+and 5 times" can be expressed without falling back to [Unlimited]. [ExpectationsWereMet] reports an
+error if the actual call count falls outside card's bounds once the test ends.
+
+Like [Unlimited], a cardinality with no upper bound ([AtLeast], [AnyTimes]) can only be the last
+override in the chain, since there would be no way to tell when control should pass to whatever
+follows it.
+*/
+func OverrideCardinality[T any](ctx context.Context, org T, card Cardinality, mock T) T {
+	if reflect.ValueOf(org).Kind() != reflect.Func || reflect.ValueOf(mock).Kind() != reflect.Func {
+		panic("OverrideCardinality() can be called only for function/method")
+	}
+
+	c := currentChain(ctx)
+
+	if c.collecting == nil {
+		if lg := lastGroup(c); lg != nil && groupHasUnlimited(lg) {
+			panic("Cannot override the function because previous override in chain has unlimited number of repetitions, therefore this override is unreachable")
 		}
 	}
-	return len(expectations)
+
+	orgPointer := reflect.ValueOf(org).UnsafePointer()
+	mockPointer := reflect.ValueOf(mock).UnsafePointer()
+
+	for _, e := range c.flatten() {
+		if e.orgAddr == orgPointer && e.expCount == Always {
+			panic("Cannot override function that was previously overridden with 'Always' count")
+		}
+	}
+
+	typ := reflect.ValueOf(org).Type()
+
+	expectedCall := &Expect{
+		ctx:         ctx,
+		expCount:    Unlimited, // chain treats it like Unlimited - only card's bounds matter
+		card:        &card,
+		mockAddr:    mockPointer,
+		orgAddr:     orgPointer,
+		orgType:     typ,
+		orgVariadic: typ.IsVariadic(),
+		orgName:     runtime.FuncForPC(uintptr(orgPointer)).Name(),
+	}
+
+	v := reflect.MakeFunc(
+		typ,
+		func(args []reflect.Value) []reflect.Value {
+			expectedCall.args = args
+			ret := make([]reflect.Value, typ.NumOut())
+			for i := range ret {
+				ret[i] = reflect.Zero(typ.Out(i))
+			}
+			return ret
+		})
+
+	var expectedArgsFunc T
+	fn := reflect.ValueOf(&expectedArgsFunc).Elem()
+	fn.Set(v)
+
+	var g *OverrideGroup
+	if c.collecting != nil {
+		g = c.collecting
+		g.expectations = append(g.expectations, expectedCall)
+	} else {
+		g = &OverrideGroup{expectations: []*Expect{expectedCall}}
+		c.groups = append(c.groups, g)
+	}
+
+	if g == headGroup(c) {
+		expectedCall.install()
+	}
+
+	return expectedArgsFunc
 }
 
 /*
@@ -254,13 +440,38 @@ It is important to call ExpectationsWereMet at the end of test case to restore o
 of overridden functions.
 */
 func ExpectationsWereMet() error {
-	defer func() { expectations = nil }()
+	v, ok := chains.Load(goroutineID())
+	if !ok {
+		return nil // nothing was overridden on this goroutine
+	}
+	return v.(*expectChain).expectationsWereMet()
+}
+
+// expectationsWereMet is the per-chain implementation behind the package-level [ExpectationsWereMet].
+func (c *expectChain) expectationsWereMet() error {
+	defer func() { c.groups = nil }()
+
+	all := c.flatten()
 
 	var err error
-	for i, e := range expectations {
-		reset(e.orgAddr, e.orgPrologue)
+	for i, e := range all {
+		e.reset()
+		// a Cardinality-based expectation only cares about its own bounds, not the exact/sentinel
+		// expCount rules below - reaching its upper bound (if any) already advanced it out of the
+		// chain via [Expectation], so one still present here just needs its final count checked
+		if e.card != nil {
+			if !e.card.met(e.actCount) {
+				err = errors.Join(err, fmt.Errorf("function %s was called %d time(s), want %s",
+					e.orgName, e.actCount, e.card))
+			}
+			continue
+		}
+		// Maybe is optional - it is never an error, called or not, and doesn't stop the remaining checks
+		if e.expCount == Maybe {
+			continue
+		}
 		// Always or last expectation is Unlimited - not an error
-		if e.expCount == Unlimited && i == len(expectations)-1 || e.expCount == Always {
+		if e.expCount == Unlimited && i == len(all)-1 || e.expCount == Always {
 			break
 		}
 		if e.actCount == 0 {
@@ -278,7 +489,16 @@ func ExpectationsWereMet() error {
 }
 
 /*
-TestingContext returns the context with embedded [testing.T].
+TestingContext returns the context with embedded [testing.T]. Pass it (or a context derived from it) as
+the ctx argument to [Override]/[Group].
+
+The first override registered through the returned context (or a context derived from it) also
+registers a [testing.T.Cleanup] handler for t's goroutine: when the test (or subtest) finishes, whether
+it returns normally or panics, the handler calls [ExpectationsWereMet] and forwards any error to
+t.Errorf, then restores the original prologue of every function overridden through this context - there
+is no need to call ExpectationsWereMet or Reset/ResetAll by hand just to avoid leaking a patched
+prologue into the next test. Each goroutine (so each t.Parallel() subtest) gets its own independent set
+of overrides, tracked separately from any other goroutine's.
 */
 func TestingContext(t *testing.T) context.Context {
 	return context.WithValue(context.Background(), testingKey, t)
@@ -39,3 +39,20 @@ func override(orgPointer, mockPointer unsafe.Pointer) []byte {
 func reset(ptr unsafe.Pointer, buf []byte) {
 	replacePrologue(ptr, buf) // OS-specific
 }
+
+// buildJump returns a stand-alone JMP instruction jumping from 'from' to 'to'. It uses the same
+// encoding as the JMP override() writes over a function's prologue, but anchored at an arbitrary
+// address, which is what [Hook]'s trampoline needs to jump back into the original function past the
+// bytes it copied out of the prologue.
+func buildJump(from, to unsafe.Pointer) []byte {
+	buf := make([]byte, jmpInstrLength)
+	buf[0] = jmpInstrCode
+	jumpLocation := uintptr(to) - (uintptr(from) + jmpInstrLength)
+	binary.NativeEndian.PutUint32(buf[1:], uint32(jumpLocation))
+	return buf
+}
+
+// flushTrampoline is a no-op on amd64, which keeps its instruction cache coherent with data writes
+// automatically; it exists so [Hook] can call it unconditionally, the way override()/reset() call
+// the arm64 cache flush unconditionally too.
+func flushTrampoline(unsafe.Pointer, int) {}
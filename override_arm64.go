@@ -1,5 +1,5 @@
 // This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
-// Copyright (c) 2024 Ilya Caramishev. All rights reserved.
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -26,23 +26,38 @@ import "C"
 
 import (
 	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
 	"unsafe"
+
+	"golang.org/x/sys/unix"
 )
 
 const instrLength = 4
-const jmpInstrCode = uint8(0x14) // B instruction
+const bInstrOpcode = uint32(0x05) << 26 // top 6 bits of an unconditional B instruction
+
+// maxBranchRange is the furthest a B instruction's signed, word-aligned 26-bit immediate can reach:
+// 2^25 words in either direction, i.e. roughly ±128 MiB from the instruction patching the prologue.
+const maxBranchRange = 1 << 27
+
+// islands maps orgPointer to the executable page [buildIsland] allocated for it, for [reset] to free
+// once the override is gone. Only functions patched via an island - the mock was further away than a
+// direct B instruction can reach - have an entry here.
+var islands sync.Map // map[unsafe.Pointer]unsafe.Pointer
 
 func override(orgPointer, mockPointer unsafe.Pointer) []byte {
 	funcPrologue := unsafe.Slice((*uint8)(orgPointer), instrLength)
 	orgPrologue := make([]byte, instrLength)
 	copy(orgPrologue, funcPrologue)
 
-	newPrologue := make([]byte, instrLength)
-	jumpLocation := (uintptr(mockPointer) - (uintptr(orgPointer))) / uintptr(instrLength)
-	binary.NativeEndian.PutUint32(newPrologue, uint32(jumpLocation))
-	newPrologue[3] = jmpInstrCode
+	target := mockPointer
+	if !inBranchRange(orgPointer, mockPointer) {
+		target = buildIsland(orgPointer, mockPointer)
+		islands.Store(orgPointer, target)
+	}
 
-	replacePrologue(orgPointer, newPrologue) // OS-specific
+	replacePrologue(orgPointer, encodeB(orgPointer, target)) // OS-specific
 
 	C.flush_cache(C.uint64_t(uintptr(orgPointer)), C.size_t(instrLength))
 
@@ -53,4 +68,106 @@ func reset(ptr unsafe.Pointer, buf []byte) {
 	replacePrologue(ptr, buf) // OS-specific
 
 	C.flush_cache(C.uint64_t(uintptr(ptr)), C.size_t(instrLength))
+
+	if island, ok := islands.LoadAndDelete(ptr); ok {
+		if err := unix.MunmapPtr(island.(unsafe.Pointer), uintptr(os.Getpagesize())); err != nil {
+			panic(fmt.Sprintf("cannot release trampoline island: %v", err))
+		}
+	}
+}
+
+// inBranchRange reports whether a single B instruction at from can reach to directly.
+func inBranchRange(from, to unsafe.Pointer) bool {
+	distance := int64(uintptr(to)) - int64(uintptr(from))
+	return distance > -maxBranchRange && distance < maxBranchRange
+}
+
+// encodeB returns a stand-alone B instruction jumping from 'from' to 'to', which must be within
+// [maxBranchRange] of each other - override() and [buildIsland] take care of that, routing anything
+// further through an island.
+func encodeB(from, to unsafe.Pointer) []byte {
+	buf := make([]byte, instrLength)
+	imm26 := uint32((int64(uintptr(to))-int64(uintptr(from)))/instrLength) & 0x03FFFFFF
+	binary.NativeEndian.PutUint32(buf, bInstrOpcode|imm26)
+	return buf
+}
+
+// buildJump returns a stand-alone B instruction jumping from 'from' to 'to'. It uses the same
+// encoding as the B override() writes over a function's prologue, but anchored at an arbitrary
+// address, which is what [Hook]'s trampoline needs to jump back into the original function past the
+// bytes it copied out of the prologue - always a few bytes forward, so always in range.
+func buildJump(from, to unsafe.Pointer) []byte {
+	return encodeB(from, to)
+}
+
+/*
+buildIsland allocates a single RWX page within [maxBranchRange] of orgPointer and writes the standard
+AArch64 long-branch veneer into it - LDR X16, #8 ; BR X16 ; <64-bit absolute address> - so that a B
+instruction at orgPointer, which can only reach an address within ±128 MiB, can still end up jumping
+anywhere in the 64-bit address space by landing on the island first.
+*/
+func buildIsland(orgPointer, mockPointer unsafe.Pointer) unsafe.Pointer {
+	island, err := reserveIsland(orgPointer)
+	if err != nil {
+		panic(err)
+	}
+
+	buf := encodeIslandVeneer(mockPointer)
+	copy(unsafe.Slice((*byte)(island), len(buf)), buf[:])
+
+	C.flush_cache(C.uint64_t(uintptr(island)), C.size_t(len(buf)))
+
+	return island
+}
+
+// islandVeneerLength is the size of the veneer [encodeIslandVeneer] writes: two 4-byte instructions
+// followed by an 8-byte literal pool entry holding the absolute target address.
+const islandVeneerLength = 16
+
+// encodeIslandVeneer returns the standard AArch64 long-branch veneer - LDR X16, #8 ; BR X16 ;
+// <64-bit absolute address> - that lands on target regardless of distance, which is what lets an
+// island forward a B instruction anywhere in the 64-bit address space.
+func encodeIslandVeneer(target unsafe.Pointer) [islandVeneerLength]byte {
+	var buf [islandVeneerLength]byte
+	binary.LittleEndian.PutUint32(buf[0:], 0x58000050)              // LDR X16, #8
+	binary.LittleEndian.PutUint32(buf[4:], 0xD61F0200)              // BR X16
+	binary.LittleEndian.PutUint64(buf[8:], uint64(uintptr(target))) // literal pool: target address
+	return buf
+}
+
+// islandSearchStep and islandSearchAttempts bound how far reserveIsland looks for a free page around
+// orgPointer: up to islandSearchAttempts steps of islandSearchStep bytes in each direction, comfortably
+// inside maxBranchRange so the mmap hint never has to be taken at face value.
+const islandSearchStep = 1 << 20 // 1 MiB
+const islandSearchAttempts = 96
+
+// reserveIsland asks the kernel for an anonymous RWX page near orgPointer, retrying at increasing
+// offsets - mmap's address argument is only a hint, so a busy hint is skipped rather than failing
+// outright - until it gets back a page that a B instruction at orgPointer can actually reach.
+func reserveIsland(orgPointer unsafe.Pointer) (unsafe.Pointer, error) {
+	pageSize := os.Getpagesize()
+	base := uintptr(orgPointer) &^ (uintptr(pageSize) - 1)
+
+	for i := 0; i < islandSearchAttempts; i++ {
+		for _, hint := range [2]uintptr{base + uintptr(i)*islandSearchStep, base - uintptr(i)*islandSearchStep} {
+			mem, err := unix.MmapPtr(-1, 0, unsafe.Pointer(hint), uintptr(pageSize),
+				unix.PROT_READ|unix.PROT_WRITE|unix.PROT_EXEC, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS)
+			if err != nil {
+				continue
+			}
+			if inBranchRange(orgPointer, mem) {
+				return mem, nil
+			}
+			unix.MunmapPtr(mem, uintptr(pageSize))
+		}
+	}
+
+	return nil, fmt.Errorf("cannot reserve an executable page within branch range of %p", orgPointer)
+}
+
+// flushTrampoline flushes len bytes of freshly-written executable memory starting at ptr - arm64
+// doesn't snoop the instruction cache on data writes, so [Hook]'s trampoline needs the same manual
+// flush override()/reset() already do for the patched prologue itself.
+func flushTrampoline(ptr unsafe.Pointer, len int) {
+	C.flush_cache(C.uint64_t(uintptr(ptr)), C.size_t(len))
 }
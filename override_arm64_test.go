@@ -0,0 +1,63 @@
+package testaroli
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestInBranchRange(t *testing.T) {
+	org := unsafe.Pointer(uintptr(0x10000))
+
+	if !inBranchRange(org, unsafe.Pointer(uintptr(0x10000)+maxBranchRange-4)) {
+		t.Error("expected the top of the range to be reachable")
+	}
+	if inBranchRange(org, unsafe.Pointer(uintptr(0x10000)+maxBranchRange)) {
+		t.Error("expected just past the top of the range to be unreachable")
+	}
+	if !inBranchRange(org, unsafe.Pointer(uintptr(0x10000)-(maxBranchRange-4))) {
+		t.Error("expected the bottom of the range to be reachable")
+	}
+}
+
+func TestEncodeBRoundTrips(t *testing.T) {
+	from := unsafe.Pointer(uintptr(0x100000))
+	to := unsafe.Pointer(uintptr(0x100000 + 0x2000000)) // 32 MiB forward, well within range
+
+	buf := encodeB(from, to)
+	word := uint32(buf[0]) | uint32(buf[1])<<8 | uint32(buf[2])<<16 | uint32(buf[3])<<24
+
+	if word&0xFC000000 != bInstrOpcode {
+		t.Errorf("opcode bits = %#x, want %#x", word&0xFC000000, bInstrOpcode)
+	}
+
+	imm26 := word & 0x03FFFFFF
+	got := int64(int32(imm26<<6) >> 6) // sign-extend the 26-bit field
+	want := (int64(uintptr(to)) - int64(uintptr(from))) / instrLength
+	if got != want {
+		t.Errorf("decoded imm26 = %d words, want %d", got, want)
+	}
+}
+
+func TestEncodeIslandVeneer(t *testing.T) {
+	target := unsafe.Pointer(uintptr(0x1234567890))
+
+	buf := encodeIslandVeneer(target)
+
+	if len(buf) != islandVeneerLength {
+		t.Fatalf("veneer length = %d, want %d", len(buf), islandVeneerLength)
+	}
+	if ldr := uint32(buf[0]) | uint32(buf[1])<<8 | uint32(buf[2])<<16 | uint32(buf[3])<<24; ldr != 0x58000050 {
+		t.Errorf("first instruction = %#x, want LDR X16, #8 (%#x)", ldr, 0x58000050)
+	}
+	if br := uint32(buf[4]) | uint32(buf[5])<<8 | uint32(buf[6])<<16 | uint32(buf[7])<<24; br != 0xD61F0200 {
+		t.Errorf("second instruction = %#x, want BR X16 (%#x)", br, 0xD61F0200)
+	}
+
+	var addr uint64
+	for i := 0; i < 8; i++ {
+		addr |= uint64(buf[8+i]) << (8 * i)
+	}
+	if addr != uint64(uintptr(target)) {
+		t.Errorf("literal pool address = %#x, want %#x", addr, uintptr(target))
+	}
+}
@@ -0,0 +1,82 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+/*
+patchMu, defined in override.go, only keeps two goroutines from calling override()/reset() at the
+same time - it does nothing about a third goroutine that is, right now, executing the very
+instructions a patch is about to overwrite. On a multicore machine that's a plain data race on
+instruction memory: a JMP can be torn in half and read back as garbage if a call lands mid-write.
+
+A genuine fix needs the runtime to stop every other M before the write and resume it after, the way
+`runtime.stopTheWorld` does internally - go:linkname-ing into that is exactly the kind of thing this
+package already does to patch function prologues, but the exported `//go:linkname` target for it
+changes shape across Go releases and crashing the whole test binary because of a patch window is a
+worse failure mode than the rare torn write it's meant to prevent. [PatchModeSTW] instead shrinks
+the window the cheap way: it locks the current goroutine to its OS thread and drops GOMAXPROCS to 1
+for the duration of the patch, so no other goroutine can be mid-instruction on another core while
+the write happens, then restores both. It is not a real stop-the-world - a goroutine already
+descheduled mid-function resumes exactly where it left off once GOMAXPROCS is restored, prologue and
+all - but it closes the only window patching itself can create.
+
+[PatchModeBestEffort], the default, keeps today's behaviour: patchMu alone. It is fine for the common
+case of sequential (non-t.Parallel()) tests, where there is no other goroutine to race against in the
+first place. Switch to PatchModeSTW before using [Always] overrides from parallel subtests, where the
+mocked function may legitimately be entered from more than one goroutine while the patch is applied.
+*/
+package testaroli
+
+import "runtime"
+
+// PatchMode selects how much synchronization [Override], [Hook] and their cleanup use when patching
+// or restoring a function prologue.
+type PatchMode int
+
+const (
+	// PatchModeBestEffort serializes patches against each other but not against goroutines that may be
+	// mid-call at the patched address. It is the default.
+	PatchModeBestEffort PatchMode = iota
+	// PatchModeSTW additionally locks the patching goroutine to its OS thread and forces GOMAXPROCS(1)
+	// for the duration of the patch, so no other goroutine can be executing on another core.
+	PatchModeSTW
+)
+
+var patchMode = PatchModeBestEffort
+
+// SetPatchMode changes how subsequent Override/Hook patches are synchronized; see [PatchModeSTW] for
+// when it is needed. It affects the whole process, not just the calling goroutine's expectation chain.
+func SetPatchMode(mode PatchMode) {
+	patchMu.Lock()
+	defer patchMu.Unlock()
+	patchMode = mode
+}
+
+// withPatchLock runs fn with patchMu held, additionally quiescing other Ms around it when the current
+// [PatchMode] is [PatchModeSTW]. Every call site that pokes at a function prologue - override(), reset()
+// and their Hook equivalents - goes through this instead of taking patchMu directly.
+func withPatchLock(fn func()) {
+	patchMu.Lock()
+	defer patchMu.Unlock()
+
+	if patchMode != PatchModeSTW {
+		fn()
+		return
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	prev := runtime.GOMAXPROCS(1)
+	defer runtime.GOMAXPROCS(prev)
+
+	fn()
+}
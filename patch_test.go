@@ -0,0 +1,37 @@
+package testaroli
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestWithPatchLockBestEffortRunsFn(t *testing.T) {
+	called := false
+	withPatchLock(func() { called = true })
+	if !called {
+		t.Error("withPatchLock did not run fn")
+	}
+}
+
+func TestWithPatchLockSTWRestoresGOMAXPROCS(t *testing.T) {
+	prev := runtime.GOMAXPROCS(0)
+	SetPatchMode(PatchModeSTW)
+	defer SetPatchMode(PatchModeBestEffort)
+
+	called := false
+	var duringSTW int
+	withPatchLock(func() {
+		called = true
+		duringSTW = runtime.GOMAXPROCS(0)
+	})
+
+	if !called {
+		t.Error("withPatchLock did not run fn")
+	}
+	if duringSTW != 1 {
+		t.Errorf("GOMAXPROCS during STW patch = %d, want 1", duringSTW)
+	}
+	if got := runtime.GOMAXPROCS(0); got != prev {
+		t.Errorf("GOMAXPROCS after STW patch = %d, want restored %d", got, prev)
+	}
+}
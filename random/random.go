@@ -0,0 +1,63 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+/*
+Package random makes code that seeds math/rand off wall-clock time (the common
+`rand.NewSource(time.Now().Unix())` pattern) reproducible in tests, by patching rand.NewSource and the
+top-level rand.Intn/rand.Int63n/rand.Float64 family via [testaroli.Override] - the same mechanism
+[clock.FakeClock] uses for time.Now - instead of threading an *rand.Rand through the code under test.
+
+	func genRandom() int {
+	    r := rand.New(rand.NewSource(time.Now().Unix()))
+	    return r.Intn(100)
+	}
+
+	func TestGenRandom(t *testing.T) {
+	    random.Deterministic(testaroli.TestingContext(t), 42)
+	    if genRandom() != genRandom() {
+	        t.Error("expected genRandom() to be reproducible once seeded deterministically")
+	    }
+	}
+*/
+package random
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/qrdl/testaroli"
+)
+
+/*
+Deterministic overrides rand.NewSource, rand.Intn, rand.Int63n and rand.Float64 for the lifetime of
+the test embedded in ctx (via [testaroli.Always]), so every one of them draws from a single
+*rand.Rand seeded with seed instead of the package's own global source. Every rand.NewSource call
+reseeds that source with seed, so code that builds its own *rand.Rand from it sees the same sequence
+each time.
+
+ctx must be created with [testaroli.TestingContext] or derived from one, the same as for
+[testaroli.Override].
+*/
+func Deterministic(ctx context.Context, seed int64) {
+	src := rand.NewSource(seed)
+	r := rand.New(src)
+
+	testaroli.Override(ctx, rand.NewSource, testaroli.Always, func(int64) rand.Source {
+		src.Seed(seed) // every rand.NewSource call restarts the same seeded sequence
+		return src
+	})
+	testaroli.Override(ctx, rand.Intn, testaroli.Always, r.Intn)
+	testaroli.Override(ctx, rand.Int63n, testaroli.Always, r.Int63n)
+	testaroli.Override(ctx, rand.Float64, testaroli.Always, r.Float64)
+}
@@ -0,0 +1,27 @@
+package random
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/qrdl/testaroli"
+)
+
+func TestDeterministicReplaysSameSequence(t *testing.T) {
+	// one subtest each, since a test can hold only one Always override of a function
+	var first, second []int
+	t.Run("first", func(st *testing.T) {
+		Deterministic(testaroli.TestingContext(st), 42)
+		first = []int{rand.Intn(100), rand.Intn(100), rand.Intn(100)}
+	})
+	t.Run("second", func(st *testing.T) {
+		Deterministic(testaroli.TestingContext(st), 42)
+		second = []int{rand.Intn(100), rand.Intn(100), rand.Intn(100)}
+	})
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("draw %d: got %d and %d for the same seed", i, first[i], second[i])
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+/*
+Every call checked with [Expect.CheckArgs] is also journaled to its chain's call log, independent of
+whether the call satisfied (or even had) an Once/Unlimited count - [Calls], [AllCalls], [CallCount] and
+[CallTimes] give a test a way to inspect that history directly, e.g. to count how many times a
+function was called with an argument matching some predicate, rather than only asserting counts up
+front. [Spy] journals to the same log for functions whose real behaviour should run unmodified. There
+is currently no hook to record a call's return values, since, unlike [Hook] and [Spy], a plain
+[Override] mock never runs a wrapper around org that could observe them - only what
+[Expect.CheckArgs] was given is captured.
+*/
+package testaroli
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+/*
+RecordedCall is one entry in a chain's call log, written by [Expect.CheckArgs]. Org identifies which
+overridden function the call was for, by the same name [ExpectationsWereMet] reports in its errors.
+*/
+type RecordedCall struct {
+	Org         string
+	Args        []any
+	GoroutineID uint64
+	Time        time.Time
+	Stack       string
+}
+
+// record appends a [RecordedCall] for e to the calling goroutine's chain. CheckArgs is always invoked
+// from the mock, i.e. from the same goroutine the call itself is on, so currentChainForDispatch always
+// resolves the right chain - the same way [Expectation] does.
+func (e Expect) record(args []any) {
+	c := currentChainForDispatch()
+	c.callLog = append(c.callLog, RecordedCall{
+		Org:         e.orgName,
+		Args:        args,
+		GoroutineID: goroutineID(),
+		Time:        timeNow(),
+		Stack:       callStack(),
+	})
+}
+
+// timeNow is time.Now, indirected so tests that need a deterministic clock can still exercise record.
+var timeNow = time.Now
+
+// callStack returns a short, human-readable stack trace for the goroutine calling into CheckArgs,
+// skipping the recorder and CheckArgs frames themselves.
+func callStack() string {
+	var pcs [16]uintptr
+	n := runtime.Callers(4, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	var s string
+	for {
+		f, more := frames.Next()
+		s += f.Function + "\n"
+		if !more {
+			break
+		}
+	}
+	return s
+}
+
+/*
+Calls returns every [RecordedCall] logged so far, for org specifically, on ctx's chain - i.e. the same
+per-goroutine chain [Override] registers against. org is matched by the same fully-qualified name
+[ExpectationsWereMet] reports in its errors, so it doesn't need to still be under an active override.
+*/
+func Calls(ctx context.Context, org any) []RecordedCall {
+	c := currentChain(ctx)
+	name := runtime.FuncForPC(reflect.ValueOf(org).Pointer()).Name()
+	var calls []RecordedCall
+	for _, rc := range c.callLog {
+		if rc.Org == name {
+			calls = append(calls, rc)
+		}
+	}
+	return calls
+}
+
+// AllCalls returns every RecordedCall logged so far on ctx's chain, across every overridden function,
+// in the order they were checked.
+func AllCalls(ctx context.Context) []RecordedCall {
+	return append([]RecordedCall{}, currentChain(ctx).callLog...)
+}
+
+// CallCount is a shorthand for len(Calls(ctx, org)).
+func CallCount(ctx context.Context, org any) int {
+	return len(Calls(ctx, org))
+}
+
+// CallTimes is a shorthand for collecting the Time field of every [Calls] entry for org, in call order.
+func CallTimes(ctx context.Context, org any) []time.Time {
+	calls := Calls(ctx, org)
+	times := make([]time.Time, len(calls))
+	for i, c := range calls {
+		times[i] = c.Time
+	}
+	return times
+}
@@ -0,0 +1,51 @@
+package testaroli
+
+import "testing"
+
+func TestRecorderCallsFiltersByOrg(t *testing.T) {
+	ctx := TestingContext(t)
+
+	Override(ctx, bar, Unlimited, func(i int) error {
+		Expectation().Expect(Any()).CheckArgs(i)
+		return nil
+	})
+
+	bar(2)
+	bar(3)
+
+	calls := Calls(ctx, bar)
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 recorded calls to bar, got %d", len(calls))
+	}
+	if calls[0].Args[0] != 2 || calls[1].Args[0] != 3 {
+		t.Errorf("recorded args out of order or wrong: %+v", calls)
+	}
+
+	if len(Calls(ctx, foo)) != 0 {
+		t.Error("Calls(ctx, foo) should be empty - foo was never overridden in this test")
+	}
+}
+
+func TestRecorderAllCallsSpansOverrides(t *testing.T) {
+	ctx := TestingContext(t)
+
+	Override(ctx, bar, Once, func(i int) error {
+		Expectation().Expect(Any()).CheckArgs(i)
+		return nil
+	})
+	Override(ctx, baz, Once, func(i int) error {
+		Expectation().Expect(Any()).CheckArgs(i)
+		return nil
+	})
+
+	bar(2)
+	baz(1)
+
+	all := AllCalls(ctx)
+	if len(all) != 2 {
+		t.Fatalf("expected 2 recorded calls total, got %d", len(all))
+	}
+	if all[0].Org == all[1].Org {
+		t.Error("expected recorded calls for two distinct functions")
+	}
+}
@@ -0,0 +1,56 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+package testaroli
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// registry maps the fully-qualified name used in a [LoadManifest] manifest (e.g. "mypkg.bar",
+// "os.(*File).Read") to the actual function value, since Go has no built-in way to go from a name
+// string back to a function pointer at runtime.
+var registry sync.Map // map[string]any
+
+/*
+Register makes fn available to [LoadManifest] under name, which manifest entries reference via their
+`target` field. name is conventionally the same fully-qualified form used when overriding the function
+by hand, e.g. "mypkg.bar" for a function or "os.(*File).Read" for a method - but any string is accepted,
+as long as the manifest uses the same one.
+
+Register is typically called once per target, from an init function or test helper; `testaroli-gen`
+emits only the Expect<Name> wrappers, not these calls:
+
+	func init() {
+	    Register("mypkg.bar", bar)
+	    Register("os.(*File).Read", (*os.File).Read)
+	}
+*/
+func Register(name string, fn any) {
+	if reflect.ValueOf(fn).Kind() != reflect.Func {
+		panic("Register() can be called only for function/method")
+	}
+	registry.Store(name, fn)
+}
+
+// lookup returns the function registered under name, or an error naming the problem.
+func lookup(name string) (any, error) {
+	v, ok := registry.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("%q is not registered - call Register(%q, ...) first", name, name)
+	}
+	return v, nil
+}
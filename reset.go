@@ -0,0 +1,84 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+package testaroli
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+/*
+Reset removes the first still-pending override of org from the current goroutine's chain, restoring its
+original, unoverridden behaviour - whether or not that override has already become active. It is the only
+way to retire an [Unlimited] or [Always] override, since neither one is ever consumed by reaching a call
+count the way a counted override is; see [Expectation] for that normal case. If org was overridden more
+than once, the overrides behind the one just removed shuffle forward exactly as they would if it had run
+out its count normally.
+
+Reset panics if org is not a function, and is a no-op if org was never overridden.
+*/
+func Reset(org any) {
+	if reflect.ValueOf(org).Kind() != reflect.Func {
+		panic("Reset() can be called only for function/method")
+	}
+	resetMatching(currentChainForDispatch(), reflect.ValueOf(org).UnsafePointer(), false)
+}
+
+/*
+ResetAll is like [Reset], but removes every pending override of org, not just the first - useful when org
+was overridden more than once (e.g. an [Always] override alongside a counted one) and all of them need to
+go together.
+
+ResetAll panics if org is not a function, and is a no-op if org was never overridden.
+*/
+func ResetAll(org any) {
+	if reflect.ValueOf(org).Kind() != reflect.Func {
+		panic("ResetAll() can be called only for function/method")
+	}
+	resetMatching(currentChainForDispatch(), reflect.ValueOf(org).UnsafePointer(), true)
+}
+
+// resetMatching removes every [Expect] in c whose orgAddr is orgPointer, or just the first one unless all
+// is set, restoring the original prologue for any of them that had already been installed, then patches
+// whichever group is now at the head of the chain in case removal unblocked it.
+func resetMatching(c *expectChain, orgPointer unsafe.Pointer, all bool) {
+	for {
+		e := findByOrgAddr(c, orgPointer)
+		if e == nil {
+			break
+		}
+		if e.installed {
+			e.reset()
+		}
+		removeExpectation(c, e)
+		if !all {
+			break
+		}
+	}
+	overrideNextInChain(c)
+}
+
+// findByOrgAddr returns the first [Expect] registered in c for orgPointer, or nil if org was never
+// overridden on this chain.
+func findByOrgAddr(c *expectChain, orgPointer unsafe.Pointer) *Expect {
+	for _, g := range c.groups {
+		for _, e := range g.expectations {
+			if e.orgAddr == orgPointer {
+				return e
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+package testaroli
+
+import (
+	"context"
+	"reflect"
+)
+
+/*
+ReturnBuilder accumulates canned return values for a function overridden via [OverrideReturn], so that
+the common "just return these values" mock can be declared without writing a closure. Each call to
+[ReturnBuilder.Return] registers the values for one run, in the order [Expectation] dispatches them;
+once the last registered run is used up, it is repeated for all further calls.
+*/
+type ReturnBuilder[T any] struct {
+	typ     reflect.Type
+	seq     [][]reflect.Value
+	expArgs [][]any
+}
+
+/*
+OverrideReturn is like [Override], but instead of a hand-written mock closure it returns a
+*ReturnBuilder[T] whose [ReturnBuilder.Return] and [ReturnBuilder.ReturnSeq] describe the canned return
+values. Values are type-checked against T's out-types via reflection as soon as they are registered,
+so a mismatch panics at override time rather than when the mock eventually runs. It saves writing a
+closure for the common case where a mock only needs to return values, e.g.:
+
+	OverrideReturn[func(int) error](TestingContext(t), bar, Once).Return(ErrInvalid)
+
+Arguments passed to the overridden function are not checked unless [ReturnBuilder.ExpectArgs] is also
+used; for anything beyond plain value/[Matcher] comparison, use [Override] together with [Expectation]
+and [Expect.CheckArgs] directly.
+*/
+func OverrideReturn[T any](ctx context.Context, org T, count int) *ReturnBuilder[T] {
+	typ := reflect.TypeOf(org)
+	if typ == nil || typ.Kind() != reflect.Func {
+		panic("OverrideReturn() can be called only for function/method")
+	}
+
+	rb := &ReturnBuilder[T]{typ: typ}
+
+	mockVal := reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		e := Expectation()
+		if exp := rb.expectedArgsFor(e.RunNumber()); exp != nil {
+			actual := make([]any, len(args))
+			for i, a := range args {
+				actual[i] = a.Interface()
+			}
+			e.Expect(exp...).CheckArgs(actual...)
+		}
+		return rb.resultsFor(e.RunNumber())
+	})
+	var mock T
+	reflect.ValueOf(&mock).Elem().Set(mockVal)
+
+	Override(ctx, org, count, mock)
+
+	return rb
+}
+
+/*
+Return registers vals as the return values for the next run. It panics immediately if len(vals) doesn't
+match T's number of return values, or if a value isn't assignable to the corresponding out-type.
+*/
+func (rb *ReturnBuilder[T]) Return(vals ...any) *ReturnBuilder[T] {
+	rb.seq = append(rb.seq, valuesToResults(rb.typ, vals))
+	return rb
+}
+
+/*
+ExpectArgs registers args as the expected arguments for the next run, checked via [Expect.Expect] and
+[Expect.CheckArgs] the same way a hand-written closure would, without giving up the closure-free
+ReturnBuilder form, e.g.
+
+	OverrideReturn[func(int) error](ctx, bar, Once).ExpectArgs(42).Return(nil)
+
+Like [ReturnBuilder.Return], if ExpectArgs is called fewer times than the override runs, the last
+registered run's arguments are reused for every later call. A run for which ExpectArgs was never called
+isn't checked at all.
+*/
+func (rb *ReturnBuilder[T]) ExpectArgs(args ...any) *ReturnBuilder[T] {
+	rb.expArgs = append(rb.expArgs, args)
+	return rb
+}
+
+// expectedArgsFor returns the arguments registered for the given zero-based run via ExpectArgs, repeating
+// the last registered run once the sequence is exhausted, or nil if ExpectArgs was never called.
+func (rb *ReturnBuilder[T]) expectedArgsFor(run int) []any {
+	if len(rb.expArgs) == 0 {
+		return nil
+	}
+	if run >= len(rb.expArgs) {
+		run = len(rb.expArgs) - 1
+	}
+	return rb.expArgs[run]
+}
+
+/*
+ReturnSeq is equivalent to calling [ReturnBuilder.Return] once per element of perCall, in order, e.g.
+
+	OverrideReturn[func() (int, error)](ctx, counter, 3).ReturnSeq(
+	    []any{1, nil},
+	    []any{2, nil},
+	    []any{0, ErrInvalid},
+	)
+*/
+func (rb *ReturnBuilder[T]) ReturnSeq(perCall ...[]any) *ReturnBuilder[T] {
+	for _, vals := range perCall {
+		rb.Return(vals...)
+	}
+	return rb
+}
+
+// resultsFor returns the reflect.Values registered for the given zero-based run, repeating the last
+// registered run once the sequence is exhausted.
+func (rb *ReturnBuilder[T]) resultsFor(run int) []reflect.Value {
+	if len(rb.seq) == 0 {
+		panic("ReturnBuilder: no return values registered, call Return() or ReturnSeq() first")
+	}
+	if run >= len(rb.seq) {
+		run = len(rb.seq) - 1
+	}
+	return rb.seq[run]
+}
+
+// valuesToResults converts the given literal values into reflect.Values matching typ's out-types,
+// panicking with a descriptive message on arity or assignability mismatch. Used by both
+// [ReturnBuilder.Return] and [LoadManifest]'s manifest-driven returns.
+func valuesToResults(typ reflect.Type, values []any) []reflect.Value {
+	if len(values) != typ.NumOut() {
+		panic("number of values doesn't match number of return values of overridden function")
+	}
+	results := make([]reflect.Value, len(values))
+	for i, val := range values {
+		outType := typ.Out(i)
+		if val == nil {
+			results[i] = reflect.Zero(outType)
+			continue
+		}
+		v := reflect.ValueOf(val)
+		if !v.Type().AssignableTo(outType) {
+			panic("value of type " + v.Type().String() + " is not assignable to return type " + outType.String())
+		}
+		results[i] = v
+	}
+	return results
+}
@@ -0,0 +1,94 @@
+package testaroli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOverrideReturn(t *testing.T) {
+	dummy := errors.New("dummy")
+	OverrideReturn[func(int) error](TestingContext(t), bar, Once).Return(dummy)
+
+	err := foo(1)
+
+	testError(t, dummy, err)
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestOverrideReturnRepeatsLastRun(t *testing.T) {
+	dummy := errors.New("dummy")
+	OverrideReturn[func(int) error](TestingContext(t), baz, Unlimited).Return(dummy)
+
+	if err := baz(1); !errors.Is(err, dummy) {
+		t.Errorf("got %v, expected %v", err, dummy)
+	}
+	if err := baz(2); !errors.Is(err, dummy) {
+		t.Errorf("got %v, expected %v", err, dummy)
+	}
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestOverrideReturnExpectArgs(t *testing.T) {
+	dummy := errors.New("dummy")
+	OverrideReturn[func(int) error](TestingContext(t), bar, Once).ExpectArgs(42).Return(dummy)
+
+	testError(t, dummy, bar(42))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestOverrideReturnExpectArgsMismatchFails(t *testing.T) {
+	ok := t.Run("subtest", func(st *testing.T) {
+		OverrideReturn[func(int) error](TestingContext(st), bar, Once).ExpectArgs(42).Return(nil)
+		bar(7) // wrong argument - ExpectArgs(42) must report a mismatch
+	})
+	if ok {
+		t.Error("expected ExpectArgs mismatch to fail the test")
+	}
+}
+
+func TestOverrideReturnSeq(t *testing.T) {
+	dummy := errors.New("dummy")
+	OverrideReturn[func(int) error](TestingContext(t), baz, 3).ReturnSeq(
+		[]any{nil},
+		[]any{dummy},
+		[]any{nil},
+	)
+
+	testError(t, nil, baz(1))
+	testError(t, dummy, baz(2))
+	testError(t, nil, baz(3))
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestOverrideReturnWrongValueCountPanics(t *testing.T) {
+	dummy := errors.New("dummy")
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+		ExpectationsWereMet()
+	}()
+
+	OverrideReturn[func(int) error](TestingContext(t), bar, Once).Return(dummy, dummy)
+}
+
+func TestOverrideReturnWrongValueTypePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+		ExpectationsWereMet()
+	}()
+
+	OverrideReturn[func(int) error](TestingContext(t), bar, Once).Return("not an error")
+}
+
+func TestOverrideReturnNotFuncPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic")
+		}
+	}()
+
+	OverrideReturn[int](TestingContext(t), 42, Once)
+}
@@ -0,0 +1,66 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ((linux || darwin) && (amd64 || arm64)) || (windows && amd64)
+
+/*
+By default two parallel subtests that [Override] the same function race with each other: both patch
+the same prologue, so whichever installs or restores it last wins, and a mock meant for one subtest can
+end up running inside the other. [GoroutineLocal] scope fixes that by patching the prologue only once,
+to a small dispatcher shared by every goroutine that overrides that function, instead of going through
+[Override] again for each one. The dispatcher looks up the calling goroutine's own mock and falls back
+to running org's real body when the calling goroutine hasn't registered one.
+
+GoroutineLocal's dispatcher is built on top of the same trampoline machinery [Hook] uses, so it is
+currently only available on Linux / x86_64 and Linux / ARM64; see scope_linux.go and scope_other.go.
+*/
+package testaroli
+
+import "context"
+
+// Scope controls how widely an [Override] mock is visible once its prologue patch is installed.
+type Scope int
+
+const (
+	// ScopeProcess, the default, is today's behaviour: the mock is visible to every goroutine for as
+	// long as the override is active. See [GoroutineLocal] for parallel subtests that each override the
+	// same function independently.
+	ScopeProcess Scope = iota
+	// GoroutineLocal makes the mock visible only to the goroutine that registered it; every other
+	// goroutine, including ones that goroutine starts, keeps running org's real body, so t.Parallel() subtests can each override the same function without racing on it. Currently
+	// only supported on Linux / x86_64 and Linux / ARM64; see scope_other.go.
+	GoroutineLocal
+)
+
+type scopeKey int
+
+const scopeContextKey = scopeKey(1)
+
+/*
+WithScope returns a context, derived from ctx, that makes the next [Override] call made with it use
+scope s instead of the default [ScopeProcess]. Pass the result where [TestingContext]'s own result
+would normally go:
+
+	Override(WithScope(TestingContext(t), GoroutineLocal), accStatus, Once, func(id int) string {
+	    Expectation().CheckArgs(id)
+	    return "closed"
+	})(42)
+*/
+func WithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeContextKey, s)
+}
+
+// scopeOf reports the [Scope] requested via [WithScope] on ctx, or [ScopeProcess] if none was set.
+func scopeOf(ctx context.Context) Scope {
+	s, _ := ctx.Value(scopeContextKey).(Scope)
+	return s
+}
@@ -0,0 +1,102 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && (amd64 || arm64)
+
+package testaroli
+
+import (
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+/*
+scopedDispatch is installed at orgPointer the first time any goroutine overrides it with
+[GoroutineLocal] scope: org's prologue is patched exactly once, to jump into the dispatcher, and every
+goroutine after the first just adds itself to mocks instead of touching the prologue again.
+*/
+type scopedDispatch struct {
+	orgPrologue []byte
+	trampoline  unsafe.Pointer
+	mocks       sync.Map // map[uint64]unsafe.Pointer: goroutine ID -> mock entry point
+	refs        int      // number of goroutines currently registered, protected by scopedMu
+}
+
+var scopedMu sync.Mutex
+var scopedDispatches sync.Map // map[unsafe.Pointer]*scopedDispatch
+
+// funcValue builds a reflect.Value of function type typ whose code pointer is code - the same trick
+// [setFuncCode] plays for a statically typed variable, except starting from a reflect.Type rather than
+// a generic parameter, since by the time an [Expect] reaches registerScoped it has already lost T.
+func funcValue(typ reflect.Type, code unsafe.Pointer) reflect.Value {
+	fv := &funcval{fn: uintptr(code)}
+	p := unsafe.Pointer(fv)
+	return reflect.NewAt(typ, unsafe.Pointer(&p)).Elem()
+}
+
+// registerScoped arranges for mockPointer to be called only when org, of type orgType, is invoked from
+// the current goroutine. It installs the shared dispatcher for orgPointer - patching its prologue
+// exactly once - the first time any goroutine overrides it with [GoroutineLocal] scope.
+func registerScoped(orgType reflect.Type, orgPointer, mockPointer unsafe.Pointer) {
+	scopedMu.Lock()
+	defer scopedMu.Unlock()
+
+	v, loaded := scopedDispatches.Load(orgPointer)
+	var d *scopedDispatch
+	if loaded {
+		d = v.(*scopedDispatch)
+	} else {
+		d = &scopedDispatch{}
+
+		dispatcher := reflect.MakeFunc(orgType, func(args []reflect.Value) []reflect.Value {
+			if m, ok := d.mocks.Load(goroutineID()); ok {
+				return funcValue(orgType, m.(unsafe.Pointer)).Call(args)
+			}
+			return funcValue(orgType, d.trampoline).Call(args)
+		})
+		holder := reflect.New(orgType).Elem()
+		holder.Set(dispatcher)
+		dispatcherPointer := holder.UnsafePointer()
+
+		withPatchLock(func() {
+			d.orgPrologue = override(orgPointer, dispatcherPointer) // call arch-specific function
+			d.trampoline = buildTrampoline(orgPointer, d.orgPrologue)
+		})
+		scopedDispatches.Store(orgPointer, d)
+	}
+
+	d.refs++
+	d.mocks.Store(goroutineID(), mockPointer)
+}
+
+// unregisterScoped drops the calling goroutine's mock for orgPointer, restoring org's original
+// prologue once the last goroutine sharing the dispatcher is done with it.
+func unregisterScoped(orgPointer unsafe.Pointer) {
+	v, ok := scopedDispatches.Load(orgPointer)
+	if !ok {
+		return
+	}
+	d := v.(*scopedDispatch)
+	d.mocks.Delete(goroutineID())
+
+	scopedMu.Lock()
+	defer scopedMu.Unlock()
+	d.refs--
+	if d.refs > 0 {
+		return
+	}
+	scopedDispatches.Delete(orgPointer)
+	withPatchLock(func() {
+		reset(orgPointer, d.orgPrologue) // call arch-specific function
+	})
+}
@@ -0,0 +1,33 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build (darwin && (amd64 || arm64)) || (windows && amd64)
+
+package testaroli
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+// registerScoped and unregisterScoped have no implementation outside Linux, since [GoroutineLocal]'s
+// dispatcher is built on [Hook]'s trampoline machinery, which is Linux-only; see scope_linux.go.
+
+func registerScoped(orgType reflect.Type, orgPointer, mockPointer unsafe.Pointer) {
+	panic(fmt.Sprintf("GoroutineLocal scope is not supported on %s", runtime.GOOS))
+}
+
+func unregisterScoped(orgPointer unsafe.Pointer) {
+	panic(fmt.Sprintf("GoroutineLocal scope is not supported on %s", runtime.GOOS))
+}
@@ -0,0 +1,42 @@
+//go:build linux && (amd64 || arm64)
+
+package testaroli
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestScopeGoroutineLocalIsolatesOverride(t *testing.T) {
+	Override(WithScope(TestingContext(t), GoroutineLocal), bar, Once, func(i int) error {
+		Expectation().CheckArgs(i)
+		return nil
+	})(2)
+
+	var wg sync.WaitGroup
+	var otherErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		otherErr = bar(3) // a goroutine that never registered a mock must still see bar's real body
+	}()
+	wg.Wait()
+
+	if err := bar(2); err != nil {
+		t.Errorf("mocked goroutine got %v, want nil", err)
+	}
+	if otherErr == nil || otherErr.Error() != "even" {
+		t.Errorf("other goroutine's call was affected by the override, got %v", otherErr)
+	}
+
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestScopeProcessIsDefault(t *testing.T) {
+	if scopeOf(TestingContext(t)) != ScopeProcess {
+		t.Errorf("a context not passed through WithScope should default to ScopeProcess")
+	}
+	if scopeOf(WithScope(TestingContext(t), GoroutineLocal)) != GoroutineLocal {
+		t.Errorf("WithScope should make scopeOf report the scope it was given")
+	}
+}
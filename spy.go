@@ -0,0 +1,59 @@
+package testaroli
+
+import "fmt"
+
+/*
+Cardinality describes how many times an overridden function is allowed to be called, generalising the
+single exact count accepted by [Override]. Build one with [Times], [MinTimes], [MaxTimes], [AnyTimes],
+[AtLeast], [AtMost] or [Between], and pass it to [OverrideCardinality].
+*/
+type Cardinality struct {
+	min, max int // max == -1 means unbounded
+}
+
+// Times returns a [Cardinality] requiring exactly n calls.
+func Times(n int) Cardinality { return Cardinality{min: n, max: n} }
+
+// MinTimes returns a [Cardinality] requiring at least n calls, with no upper bound.
+func MinTimes(n int) Cardinality { return Cardinality{min: n, max: -1} }
+
+// MaxTimes returns a [Cardinality] allowing at most n calls, with no lower bound.
+func MaxTimes(n int) Cardinality { return Cardinality{min: 0, max: n} }
+
+// AnyTimes returns a [Cardinality] allowing any number of calls, including zero.
+func AnyTimes() Cardinality { return Cardinality{min: 0, max: -1} }
+
+// AtLeast returns a [Cardinality] requiring at least n calls, with no upper bound - an alias for
+// [MinTimes] under the name used by [OverrideCardinality].
+func AtLeast(n int) Cardinality { return MinTimes(n) }
+
+// AtMost returns a [Cardinality] allowing at most n calls, with no lower bound - an alias for
+// [MaxTimes] under the name used by [OverrideCardinality].
+func AtMost(n int) Cardinality { return MaxTimes(n) }
+
+// Between returns a [Cardinality] requiring at least min and at most max calls.
+func Between(min, max int) Cardinality { return Cardinality{min: min, max: max} }
+
+func (c Cardinality) met(actCount int) bool {
+	if actCount < c.min {
+		return false
+	}
+	return c.max == -1 || actCount <= c.max
+}
+
+// String renders c the way the constructor that built it reads, e.g. "at least 2 time(s)" or
+// "between 2 and 5 time(s)", for use in [ExpectationsWereMet] failure messages.
+func (c Cardinality) String() string {
+	switch {
+	case c.min == c.max:
+		return fmt.Sprintf("exactly %d time(s)", c.min)
+	case c.max < 0 && c.min == 0:
+		return "any number of times"
+	case c.max < 0:
+		return fmt.Sprintf("at least %d time(s)", c.min)
+	case c.min == 0:
+		return fmt.Sprintf("at most %d time(s)", c.max)
+	default:
+		return fmt.Sprintf("between %d and %d time(s)", c.min, c.max)
+	}
+}
@@ -0,0 +1,116 @@
+// This file is part of Testaroli project, available at https://github.com/qrdl/testaroli
+// Copyright (c) 2024-2026 Ilya Caramishev. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at https://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && (amd64 || arm64)
+
+package testaroli
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"unsafe"
+)
+
+/*
+Spy is [Hook] with the pre/post callbacks replaced by automatic recording: org's own body still runs
+and its result is what the caller actually gets, but every call's real arguments are journalled to
+ctx's chain, the same [RecordedCall] log [Expect.CheckArgs] writes to for [Override], so they turn up
+in [Calls] and [AllCalls] without the test writing a callback at all - handy for asserting on retry
+loops and backoff sequences ("calculateBackoff was called 3 times, each roughly double the last")
+where what's interesting is the sequence of calls, not a fake return value.
+
+	func TestRetriesWithBackoff(t *testing.T) {
+	    ctx := TestingContext(t)
+	    Spy(ctx, calculateBackoff, Unlimited)
+
+	    retryWithBackoff(ctx, 3)
+
+	    calls := Calls(ctx, calculateBackoff)
+	    if len(calls) != 3 {
+	        t.Fatalf("calculateBackoff called %d times, want 3", len(calls))
+	    }
+	}
+
+count follows the same rules as [Override]'s and [Hook]'s: a positive number of expected calls, or
+[Unlimited], [Always] or [Maybe].
+*/
+func Spy[T any](ctx context.Context, org T, count int) T {
+	if reflect.ValueOf(org).Kind() != reflect.Func {
+		panic("Spy() can be called only for function/method")
+	}
+	if count < minOccurenceCount || count == 0 {
+		panic("Invalid count: must be a positive number or Unlimited/Always/Maybe")
+	}
+
+	t := Testing(ctx) // just to make sure the context is correct
+	c := currentChain(ctx)
+
+	orgPointer := reflect.ValueOf(org).UnsafePointer()
+	orgName := runtime.FuncForPC(uintptr(orgPointer)).Name()
+
+	// detour is set below, once the trampoline it points at exists; the mock closure only resolves
+	// it when actually called, which never happens before Spy returns.
+	var detour T
+	actCount := 0
+
+	typ := reflect.TypeOf(org)
+	v := reflect.MakeFunc(typ, func(args []reflect.Value) []reflect.Value {
+		actCount++
+
+		actual := make([]any, len(args))
+		for i, a := range args {
+			if a.CanInterface() {
+				actual[i] = a.Interface()
+			}
+		}
+		c.callLog = append(c.callLog, RecordedCall{
+			Org:         orgName,
+			Args:        actual,
+			GoroutineID: goroutineID(),
+			Time:        timeNow(),
+			Stack:       callStack(),
+		})
+
+		return reflect.ValueOf(detour).Call(args)
+	})
+
+	var mock T
+	fn := reflect.ValueOf(&mock).Elem()
+	fn.Set(v)
+	mockPointer := reflect.ValueOf(mock).UnsafePointer()
+
+	var orgPrologue []byte
+	var trampoline unsafe.Pointer
+	withPatchLock(func() {
+		orgPrologue = override(orgPointer, mockPointer) // call arch-specific function
+		trampoline = buildTrampoline(orgPointer, orgPrologue)
+	})
+
+	setFuncCode(&detour, trampoline)
+
+	t.Cleanup(func() {
+		withPatchLock(func() {
+			reset(orgPointer, orgPrologue)
+		})
+		if count == Unlimited || count == Always || count == Maybe {
+			return
+		}
+		if actCount == 0 {
+			t.Errorf("function %s was not called", orgName)
+		} else if actCount != count {
+			t.Errorf("function %s was called %d time(s) instead of %d", orgName, actCount, count)
+		}
+	})
+
+	return mock
+}
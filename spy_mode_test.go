@@ -0,0 +1,39 @@
+//go:build linux && (amd64 || arm64)
+
+package testaroli
+
+import "testing"
+
+func TestSpyPreservesBehaviorAndRecordsCalls(t *testing.T) {
+	ctx := TestingContext(t)
+
+	Spy(ctx, qux, Unlimited)
+
+	if err := bar(3); err == nil || err.Error() != "even" {
+		t.Errorf("qux's real result was not preserved, got %v", err)
+	}
+	if err := bar(4); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	calls := Calls(ctx, qux)
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 recorded calls to qux, got %d", len(calls))
+	}
+	if CallCount(ctx, qux) != 2 {
+		t.Errorf("CallCount = %d, want 2", CallCount(ctx, qux))
+	}
+	if len(CallTimes(ctx, qux)) != 2 {
+		t.Errorf("CallTimes returned %d entries, want 2", len(CallTimes(ctx, qux)))
+	}
+}
+
+func TestSpyOnceNotCalledIsAnError(t *testing.T) {
+	ok := t.Run("subtest", func(st *testing.T) {
+		Spy(TestingContext(st), qux, Once)
+		// deliberately never call qux - Once must report an error on cleanup
+	})
+	if ok {
+		t.Errorf("Once spy that was never called should fail the test")
+	}
+}
@@ -0,0 +1,43 @@
+package testaroli
+
+import "testing"
+
+func logFields(format string, args ...any) int {
+	return len(args)
+}
+
+func TestCheckArgsVariadicSpreadVsCollapsed(t *testing.T) {
+	Override(TestingContext(t), logFields, Once, func(format string, args ...any) int {
+		Expectation().Expect("id=%s n=%d", []any{"abc", 3}).CheckArgs(append([]any{format}, args...)...)
+		return 0
+	})("id=%s n=%d", "abc", 3)
+
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestCheckArgsVariadicCollapsedVsSpread(t *testing.T) {
+	Override(TestingContext(t), logFields, Once, func(format string, args ...any) int {
+		Expectation().Expect("id=%s n=%d", "abc", 3).CheckArgs(format, args)
+		return 0
+	})("id=%s n=%d", "abc", 3)
+
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestCheckArgsVariadicWithRest(t *testing.T) {
+	Override(TestingContext(t), logFields, Once, func(format string, args ...any) int {
+		Expectation().Expect("id=%s n=%d", Rest(Any())).CheckArgs(append([]any{format}, args...)...)
+		return 0
+	})("id=%s n=%d", "abc", 3, true)
+
+	testError(t, nil, ExpectationsWereMet())
+}
+
+func TestCheckVariadicPerArgMatchers(t *testing.T) {
+	Override(TestingContext(t), logFields, Once, func(format string, args ...any) int {
+		Expectation().CheckVariadic(args, Any(), InRange(0, 10))
+		return 0
+	})("id=%s n=%d", "abc", 3)
+
+	testError(t, nil, ExpectationsWereMet())
+}